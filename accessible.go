@@ -0,0 +1,360 @@
+// accessible output for screen readers: labelled statements without tables, charts and icons
+package main
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// reUnitAbbr - number with abbreviation of units in text from the page: "745 мм рт. ст.", "3 м/с", "71%"
+var reUnitAbbr = regexp.MustCompile(`(\d+(?:[.,]\d+)?)\s*(мм рт\. ст\.|mm Hg|м/с|m/s|%)`)
+
+// unitAbbrs - units for abbreviations in reUnitAbbr
+var unitAbbrs = map[string]string{
+	"мм рт. ст.": "mmhg",
+	"mm Hg":      "mmhg",
+	"м/с":        "mps",
+	"m/s":        "mps",
+	"%":          "percent",
+}
+
+// ----------------------------------------------------------------------------
+// whole forecast as lines of statements, each statement has a label,
+// marks shown by color (weekend, today, night) are also words
+func renderAccessible(forecastNow map[string]interface{}, forecastByHours []hourTemp, forecastNext []dayForecast, cfg config) []string {
+	title, _ := forecastNow["city"].(string)
+	if cfg.location != nil {
+		title = placeFromTitle(title) + ", " + cfg.location.String()
+	}
+	result := []string{statement(cfg.tr("place"), title)}
+
+	warnings, _ := forecastNow["warnings"].([]weatherWarning)
+	for _, warning := range warnings {
+		label := fmt.Sprintf(cfg.tr("warning_level"), cfg.tr("severity_"+warning.Severity))
+		result = append(result, statement(label, cfg.spellUnits(strings.Join(nonEmpty(warning.Title, warning.Text), ": "))))
+	}
+	if nowcast, _ := forecastNow["nowcast"].(string); nowcast != "" {
+		result = append(result, statement(cfg.tr("nowcast"), nowcast))
+	}
+
+	if cfg.view == "" || cfg.view == viewNow {
+		if termNow, ok := forecastNow["term_now"].(int); ok {
+			desc, _ := forecastNow["desc_now"].(string)
+			result = append(result, statement(cfg.tr("now"), strings.Join(nonEmpty(cfg.spellTemp(termNow), desc), ", ")))
+		}
+		for _, key := range []string{"pressure", "humidity", "wind"} {
+			if value, _ := forecastNow[key].(string); value != "" {
+				result = append(result, statement(cfg.tr(key), cfg.spellUnits(value)))
+			}
+		}
+
+		if comfort, ok := forecastNow["comfort"].(comfortMetrics); ok && cfg.comfort {
+			result = append(result, statement(cfg.tr("comfort"), ""))
+			result = append(result, cfg.accessibleValues([]string{"dew_point", "humidex", "heat_index", "wind_chill"},
+				[]*int{&comfort.DewPoint, &comfort.Humidex, comfort.HeatIndex, comfort.WindChill}, true)...)
+		}
+		if extra, ok := forecastNow["extra"].(extraPanels); ok {
+			result = append(result, statement(cfg.tr("extra"), ""))
+			result = append(result, cfg.accessibleValues([]string{"air_quality", "uv_index", "geomagnetic"},
+				[]*int{extra.AirQuality, extra.UVIndex, extra.Geomagnetic}, false)...)
+			result = append(result, cfg.accessibleValues([]string{"water_temp"}, []*int{extra.WaterTemp}, true)...)
+		}
+	}
+
+	if !cfg.noToday && len(forecastByHours) > 0 {
+		for _, hours := range splitHoursByDay(forecastByHours) {
+			day := cfg.tr("today")
+			if hours[0].Date != "" {
+				day = cfg.accessibleDate(hours[0].Date)
+			}
+			result = append(result, statement(cfg.tr("hours_forecast")+", "+day, ""))
+			result = append(result, "  "+statement(cfg.tr("trend"), cfg.trendText(hours)))
+			night := cfg.nightHours(hours)
+			for i, item := range hours {
+				result = append(result, "  "+cfg.accessibleHour(item, i < len(night) && night[i]))
+			}
+		}
+	}
+
+	if len(forecastNext) > 0 {
+		result = append(result, statement(cfg.tr("days_forecast"), ""))
+		for _, row := range forecastNext {
+			result = append(result, "  "+statement(cfg.accessibleDate(row.Date), strings.Join([]string{
+				cfg.tr("part_day") + " " + cfg.spellTemp(row.Temp),
+				cfg.tr("part_night") + " " + cfg.spellTemp(row.TempNight),
+				row.Desc,
+			}, ", ")))
+			for _, part := range row.Parts {
+				result = append(result, "    "+cfg.accessiblePart(part))
+			}
+			if row.Astro != nil {
+				for _, line := range cfg.accessibleAstro(*row.Astro) {
+					result = append(result, "    "+line)
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// ----------------------------------------------------------------------------
+// statement with label: "Label: text.", or "Label:" for heading of statements below,
+// text is from the page and is escaped, only label has color tags
+func statement(label, text string) string {
+	if text == "" {
+		return capitalize(label) + ":"
+	}
+	if !strings.HasSuffix(text, ".") {
+		text += "."
+	}
+	return capitalize(label) + ": " + colorTagsEscaper.Replace(text)
+}
+
+// ----------------------------------------------------------------------------
+// statements for values with labels, nil values are skipped
+func (cfg config) accessibleValues(labels []string, values []*int, isTemp bool) []string {
+	result := []string{}
+	for i, value := range values {
+		switch {
+		case value == nil:
+		case isTemp:
+			result = append(result, "  "+statement(cfg.tr(labels[i]), cfg.spellTemp(*value)))
+		default:
+			result = append(result, "  "+statement(cfg.tr(labels[i]), strconv.Itoa(*value)))
+		}
+	}
+	return result
+}
+
+// ----------------------------------------------------------------------------
+// date with full weekday, weekend and today in words: "Saturday 23.10, weekend"
+func (cfg config) accessibleDate(date string) string {
+	day, err := cfg.parseDate(date)
+	if err != nil {
+		return date
+	}
+	names, ok := weekdaysFull[cfg.lang]
+	if !ok {
+		names = weekdaysFull[langRu]
+	}
+
+	result := names[day.Weekday()] + " " + day.Format("02.01")
+	if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		result += ", <weekend>" + cfg.tr("weekend") + "</>"
+	}
+	if date == cfg.today() {
+		result += ", <today>" + cfg.tr("today") + "</>"
+	}
+	return result
+}
+
+// ----------------------------------------------------------------------------
+// one hour of forecast: "15:00, night: 12 degrees Celsius, rain, wind 5 meters per second."
+func (cfg config) accessibleHour(item hourTemp, night bool) string {
+	label := fmt.Sprintf("%02d:00", item.Hour)
+	if night {
+		label += ", <night>" + cfg.tr("night") + "</>"
+	}
+
+	values := []string{cfg.spellTemp(item.Temp)}
+	if _, ok := icons[item.Icon]; ok {
+		values = append(values, cfg.tr(item.Icon))
+	}
+	if item.PrecipProb != nil {
+		values = append(values, cfg.tr("precip_prob")+" "+cfg.spellUnit(strconv.Itoa(*item.PrecipProb), "percent"))
+	}
+	if item.Precip != nil {
+		values = append(values, cfg.tr("precip_amount")+" "+cfg.spellUnit(formatAmount(*item.Precip), "mm"))
+	}
+	if item.Wind != nil {
+		values = append(values, strings.ToLower(cfg.tr("wind"))+" "+cfg.spellUnit(formatAmount(*item.Wind), "mps"))
+	}
+	if item.FeelsLike != nil {
+		values = append(values, cfg.tr("feels_like")+" "+cfg.spellTemp(*item.FeelsLike))
+	}
+
+	return statement(label, strings.Join(values, ", "))
+}
+
+// ----------------------------------------------------------------------------
+// part of day from details page: "Morning: from 4 to 6 degrees Celsius, cloudy, feels like 2 degrees Celsius, ..."
+func (cfg config) accessiblePart(part dayPart) string {
+	temp := cfg.spellTemp(part.TempMin)
+	if part.TempMin != part.TempMax {
+		temp = fmt.Sprintf(cfg.tr("temp_range"), cfg.spellNumber(part.TempMin), cfg.spellTemp(part.TempMax))
+	}
+
+	values := []string{temp, part.Desc, cfg.tr("feels_like") + " " + cfg.spellTemp(part.FeelsLike)}
+	for _, prop := range []struct{ label, value string }{
+		{"wind", part.Wind},
+		{"pressure", part.Pressure},
+		{"humidity", part.Humidity},
+	} {
+		if prop.value != "" {
+			values = append(values, strings.ToLower(cfg.tr(prop.label))+" "+cfg.spellUnits(prop.value))
+		}
+	}
+
+	return statement(cfg.tr("part_"+part.Name), strings.Join(values, ", "))
+}
+
+// ----------------------------------------------------------------------------
+// sun and moon of day: "Sun: civil twilight from 06:38 to 17:49, sunrise 07:16, ..." and "Moon: ..."
+func (cfg config) accessibleAstro(astro astroInfo) []string {
+	sun := []string{}
+	if astro.CivilDawn != "" {
+		sun = append(sun, fmt.Sprintf(cfg.tr("civil_twilight"), astro.CivilDawn, astro.CivilDusk))
+	}
+	if astro.Sunrise != "" {
+		sun = append(sun, fmt.Sprintf(cfg.tr("sunrise"), astro.Sunrise), fmt.Sprintf(cfg.tr("sunset"), astro.Sunset))
+	}
+	if astro.Polar != "" {
+		sun = append(sun, cfg.tr("polar_"+astro.Polar))
+	}
+	sun = append(sun, fmt.Sprintf(cfg.tr("day_length_text"), cfg.spellDuration(astro.DayLength)))
+
+	moon := cfg.moonPhaseName(astro.MoonPhase) + ", " +
+		fmt.Sprintf(cfg.tr("moon_illuminated"), cfg.spellUnit(strconv.Itoa(astro.MoonIllumination), "percent"))
+
+	return []string{
+		statement(cfg.tr("sun"), strings.Join(sun, ", ")),
+		statement(cfg.tr("moon"), moon),
+	}
+}
+
+// ----------------------------------------------------------------------------
+// temperature trend in words instead of histogram:
+// "12 degrees Celsius at 15:00, then falls to 4 degrees Celsius by 02:00"
+func (cfg config) trendText(hours []hourTemp) string {
+	maxI, minI := 0, 0
+	for i, item := range hours {
+		if item.Temp > hours[maxI].Temp {
+			maxI = i
+		}
+		if item.Temp < hours[minI].Temp {
+			minI = i
+		}
+	}
+	if hours[maxI].Temp-hours[minI].Temp < summaryTempDelta {
+		return fmt.Sprintf(cfg.tr("trend_steady"), cfg.spellTemp(hours[0].Temp))
+	}
+
+	// turning points in order of hours, without points in the middle of rise or fall
+	extremes := []int{minI, maxI}
+	if maxI < minI {
+		extremes = []int{maxI, minI}
+	}
+	points := []int{0}
+	for _, i := range append(extremes, len(hours)-1) {
+		last := points[len(points)-1]
+		switch {
+		case i <= last || hours[i].Temp == hours[last].Temp:
+		case len(points) > 1 && (hours[i].Temp > hours[last].Temp) == (hours[last].Temp > hours[points[len(points)-2]].Temp):
+			points[len(points)-1] = i
+		default:
+			points = append(points, i)
+		}
+	}
+
+	hourText := func(item hourTemp) string { return fmt.Sprintf("%02d:00", item.Hour) }
+	parts := []string{fmt.Sprintf(cfg.tr("trend_start"), cfg.spellTemp(hours[0].Temp), hourText(hours[0]))}
+	for k := 1; k < len(points); k++ {
+		key, item := "trend_falls", hours[points[k]]
+		if item.Temp > hours[points[k-1]].Temp {
+			key = "trend_rises"
+		}
+		parts = append(parts, fmt.Sprintf(cfg.tr(key), cfg.spellTemp(item.Temp), hourText(item)))
+	}
+
+	return strings.Join(parts, ", "+cfg.tr("then")+" ")
+}
+
+// ----------------------------------------------------------------------------
+// number with word for minus: "minus 3", "0", "12"
+func (cfg config) spellNumber(value int) string {
+	if value < 0 {
+		return cfg.tr("minus") + " " + strconv.Itoa(-value)
+	}
+	return strconv.Itoa(value)
+}
+
+// ----------------------------------------------------------------------------
+// temperature with spelled out units: "minus 3 degrees Celsius"
+func (cfg config) spellTemp(temp int) string {
+	unit := "celsius"
+	if cfg.units == unitsFahrenheit {
+		unit = "fahrenheit"
+	}
+	return cfg.spellNumber(temp) + " " + cfg.unitName(unit, float64(temp))
+}
+
+// ----------------------------------------------------------------------------
+// number from text with spelled out units: "3 meters per second"
+func (cfg config) spellUnit(number, unit string) string {
+	value, err := strconv.ParseFloat(strings.Replace(number, ",", ".", 1), 64)
+	if err != nil {
+		return number
+	}
+	return number + " " + cfg.unitName(unit, value)
+}
+
+// ----------------------------------------------------------------------------
+// abbreviations of units in text from the page spelled out: "3 м/с, СЗ" -> "3 meters per second, СЗ"
+func (cfg config) spellUnits(text string) string {
+	return reUnitAbbr.ReplaceAllStringFunc(text, func(match string) string {
+		parts := reUnitAbbr.FindStringSubmatch(match)
+		return cfg.spellUnit(parts[1], unitAbbrs[parts[2]])
+	})
+}
+
+// ----------------------------------------------------------------------------
+// "9:55" -> "9 hours 55 minutes"
+func (cfg config) spellDuration(duration string) string {
+	parts := strings.SplitN(duration, ":", 2)
+	if len(parts) != 2 {
+		return duration
+	}
+	hours, minutes := strings.TrimLeft(parts[0], "0"), strings.TrimLeft(parts[1], "0")
+	if hours == "" {
+		hours = "0"
+	}
+	if minutes == "" {
+		minutes = "0"
+	}
+	return cfg.spellUnit(hours, "hour") + " " + cfg.spellUnit(minutes, "minute")
+}
+
+// ----------------------------------------------------------------------------
+// name of unit in grammatical number for value, Russian has forms for 1, 2..4 and 5..20,
+// fractions use form for 2, English has singular for 1 only
+func (cfg config) unitName(unit string, value float64) string {
+	names, ok := unitNames[cfg.lang]
+	if !ok {
+		names = unitNames[langRu]
+	}
+	forms := names[unit]
+
+	value = math.Abs(value)
+	if value != math.Trunc(value) {
+		return forms[1]
+	}
+	if cfg.lang == langEn {
+		if value == 1 {
+			return forms[0]
+		}
+		return forms[2]
+	}
+	switch n := int(value); {
+	case n%10 == 1 && n%100 != 11:
+		return forms[0]
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return forms[1]
+	default:
+		return forms[2]
+	}
+}
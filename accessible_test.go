@@ -0,0 +1,188 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_unitName(t *testing.T) {
+	tests := []struct {
+		lang  string
+		value float64
+		want  string
+	}{
+		{lang: langRu, value: 1, want: "градус Цельсия"},
+		{lang: langRu, value: 21, want: "градус Цельсия"},
+		{lang: langRu, value: 11, want: "градусов Цельсия"},
+		{lang: langRu, value: 3, want: "градуса Цельсия"},
+		{lang: langRu, value: -4, want: "градуса Цельсия"},
+		{lang: langRu, value: 13, want: "градусов Цельсия"},
+		{lang: langRu, value: 0, want: "градусов Цельсия"},
+		{lang: langRu, value: 0.6, want: "градуса Цельсия"},
+		{lang: langEn, value: 1, want: "degree Celsius"},
+		{lang: langEn, value: 21, want: "degrees Celsius"},
+		{lang: langEn, value: 0.6, want: "degrees Celsius"},
+	}
+
+	for _, tt := range tests {
+		cfg := defaultConfig()
+		cfg.lang = tt.lang
+		if got := cfg.unitName("celsius", tt.value); got != tt.want {
+			t.Errorf("unitName(%s, %v) = %q, want %q", tt.lang, tt.value, got, tt.want)
+		}
+	}
+}
+
+func Test_spellUnits(t *testing.T) {
+	tests := []struct {
+		lang string
+		text string
+		want string
+	}{
+		{lang: langEn, text: "3 м/с, СЗ", want: "3 meters per second, СЗ"},
+		{lang: langRu, text: "3,1 м/с", want: "3,1 метра в секунду"},
+		{lang: langRu, text: "745 мм рт. ст.", want: "745 миллиметров ртутного столба"},
+		{lang: langRu, text: "71%", want: "71 процент"},
+		{lang: langEn, text: "no units", want: "no units"},
+	}
+
+	for _, tt := range tests {
+		cfg := defaultConfig()
+		cfg.lang = tt.lang
+		if got := cfg.spellUnits(tt.text); got != tt.want {
+			t.Errorf("spellUnits(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func Test_trendText(t *testing.T) {
+	hours := func(temps ...int) []hourTemp {
+		result := []hourTemp{}
+		for i, temp := range temps {
+			result = append(result, hourTemp{Hour: 12 + i, Temp: temp})
+		}
+		return result
+	}
+
+	tests := []struct {
+		name  string
+		hours []hourTemp
+		want  string
+	}{
+		{
+			name:  "steady",
+			hours: hours(5, 6, 5),
+			want:  "steady, about 5 degrees Celsius",
+		},
+		{
+			name:  "falls",
+			hours: hours(5, 3, 1, -2),
+			want:  "5 degrees Celsius at 12:00, then falls to minus 2 degrees Celsius by 15:00",
+		},
+		{
+			name:  "rises and falls",
+			hours: hours(3, 6, 7, 5, 1),
+			want:  "3 degrees Celsius at 12:00, then rises to 7 degrees Celsius by 14:00, then falls to 1 degree Celsius by 16:00",
+		},
+		{
+			name:  "falls, rises and falls",
+			hours: hours(4, 1, 5, 9, 6),
+			want:  "4 degrees Celsius at 12:00, then falls to 1 degree Celsius by 13:00, then rises to 9 degrees Celsius by 15:00, then falls to 6 degrees Celsius by 16:00",
+		},
+		{
+			name:  "rises to the end",
+			hours: hours(1, 3, 5, 5),
+			want:  "1 degree Celsius at 12:00, then rises to 5 degrees Celsius by 14:00",
+		},
+	}
+
+	cfg := defaultConfig()
+	cfg.lang = langEn
+	for _, tt := range tests {
+		if got := cfg.trendText(tt.hours); got != tt.want {
+			t.Errorf("trendText() %s = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func Test_renderAccessible(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.city, cfg.lang = "moscow", langEn
+	cfg.page, cfg.pageMini, cfg.asOf = "testdata/moscow.html", "testdata/moscow-mini.html", "2021-10-22 15:00"
+	if err := validateConfig(&cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	forecastNow, forecastByHours, forecastNext := getWeather(&cfg)
+	lines := renderAccessible(forecastNow, forecastByHours, forecastNext, cfg)
+	text := strings.Join(lines, "\n")
+
+	for _, want := range []string{
+		"Now: 12 degrees Celsius, Облачно с прояснениями.",
+		"Warning, yellow level: Сильный ветер",
+		"Pressure: 745 millimeters of mercury.",
+		"Temperature trend: 12 degrees Celsius at 15:00, then falls to 4 degrees Celsius by 02:00.",
+		"17:00, <night>night</>: 11 degrees Celsius, rain, precipitation probability 70 percent",
+		"Saturday 23.10, <weekend>weekend</>: day 9 degrees Celsius, night 4 degrees Celsius",
+		"Monday 25.10: day 5 degrees Celsius, night minus 1 degree Celsius",
+		"Moon: waning gibbous",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("renderAccessible() has no %q:\n%s", want, text)
+		}
+	}
+	for _, noise := range append([]string{"─", "°", "☂", "✻", "🌖"}, HistoChars[:]...) {
+		if strings.Contains(text, noise) {
+			t.Errorf("renderAccessible() has %q:\n%s", noise, text)
+		}
+	}
+}
+
+func Test_renderAccessibleEscape(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.lang, cfg.timeZone = langEn, time.UTC
+	forecastNow := map[string]interface{}{
+		"city":     "<red>Moscow</>",
+		"term_now": 12,
+		"desc_now": "cloudy <today>",
+		"nowcast":  "rain </> in 10 min <lt>",
+	}
+
+	text := ""
+	for _, line := range renderAccessible(forecastNow, nil, nil, cfg) {
+		text += cfg.ansiColourString(line) + "\n"
+	}
+	for _, want := range []string{"<red>Moscow</>", "cloudy <today>", "rain </> in 10 min <lt>"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("renderAccessible() has no %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "\033") {
+		t.Errorf("renderAccessible() has colors from page text:\n%q", text)
+	}
+}
+
+func Test_accessibleDate(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.timeZone = time.UTC
+	cfg.clock = func() time.Time { return time.Date(2021, 10, 23, 12, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		lang string
+		date string
+		want string
+	}{
+		{lang: langEn, date: "2021-10-22", want: "Friday 22.10"},
+		{lang: langEn, date: "2021-10-23", want: "Saturday 23.10, <weekend>weekend</>, <today>today</>"},
+		{lang: langRu, date: "2021-10-24", want: "воскресенье 24.10, <weekend>выходной</>"},
+		{lang: langRu, date: "bad", want: "bad"},
+	}
+
+	for _, tt := range tests {
+		cfg.lang = tt.lang
+		if got := cfg.accessibleDate(tt.date); got != tt.want {
+			t.Errorf("accessibleDate(%s) = %q, want %q", tt.date, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+// local astronomical calculations: sunrise, sunset, civil twilight, day length and moon phase
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// sunriseAltitude - altitude of sun center at sunrise and sunset with refraction, degrees
+	sunriseAltitude = -0.833
+	// civilTwilightAltitude - altitude of sun at begin of civil dawn and end of civil dusk, degrees
+	civilTwilightAltitude = -6
+	// julianDayUnixEpoch - julian day of 1970-01-01 00:00 UTC
+	julianDayUnixEpoch = 2440587.5
+	// julianDayJ2000 - julian day of 2000-01-01 12:00 UTC
+	julianDayJ2000 = 2451545.0
+	// julianDayNewMoon - julian day of new moon 2000-01-06 18:14 UTC
+	julianDayNewMoon = 2451550.26
+	// synodicMonth - average length of lunar month, days
+	synodicMonth = 29.530588853
+	// astroTimeLayout - layout of sunrise and sunset times
+	astroTimeLayout = "15:04"
+	// astroTableWidth - width of astro columns in table of forecast by days, without moon column
+	astroTableWidth = 31
+	// moonIconWidth - display width of moon icon, emoji are double width in terminal
+	moonIconWidth = 2
+	// nightMark - mark of night hour in row of hours, one char width
+	nightMark = "☾"
+)
+
+// sun never sets or never rises
+const (
+	polarDay   = "day"
+	polarNight = "night"
+)
+
+// moonPhases - names of moon phases from new moon, for JSON and translation
+var moonPhases = []string{
+	"new_moon", "waxing_crescent", "first_quarter", "waxing_gibbous",
+	"full_moon", "waning_gibbous", "last_quarter", "waning_crescent",
+}
+
+// moonIcons - icons of moon phases
+var moonIcons = map[string]string{
+	"new_moon":        "🌑",
+	"waxing_crescent": "🌒",
+	"first_quarter":   "🌓",
+	"waxing_gibbous":  "🌔",
+	"full_moon":       "🌕",
+	"waning_gibbous":  "🌖",
+	"last_quarter":    "🌗",
+	"waning_crescent": "🌘",
+}
+
+// astroInfo - sun and moon for one day, times are local for city
+type astroInfo struct {
+	Sunrise          string `json:"sunrise,omitempty"`
+	Sunset           string `json:"sunset,omitempty"`
+	CivilDawn        string `json:"civil_dawn,omitempty"`
+	CivilDusk        string `json:"civil_dusk,omitempty"`
+	DayLength        string `json:"day_length"`      // "10:26"
+	Polar            string `json:"polar,omitempty"` // "day" or "night" when sun doesn't set or rise
+	MoonPhase        string `json:"moon_phase"`
+	MoonIllumination int    `json:"moon_illumination"` // %
+}
+
+// ----------------------------------------------------------------------------
+// sun and moon for date "2006-01-02" at point, times in time zone of city
+func computeAstro(date string, point geoPoint, cfg config) (astroInfo, error) {
+	day, err := cfg.parseDate(date)
+	if err != nil {
+		return astroInfo{}, err
+	}
+
+	result := astroInfo{}
+	sunrise, sunset, polar := sunTimes(day, point, sunriseAltitude)
+	switch polar {
+	case polarDay:
+		result.DayLength = "24:00"
+	case polarNight:
+		result.DayLength = "0:00"
+	default:
+		length := sunset.Sub(sunrise)
+		result.Sunrise = sunrise.In(day.Location()).Format(astroTimeLayout)
+		result.Sunset = sunset.In(day.Location()).Format(astroTimeLayout)
+		result.DayLength = fmt.Sprintf("%d:%02d", int(length.Hours()), int(length.Minutes())%60)
+	}
+	result.Polar = polar
+
+	if dawn, dusk, polar := sunTimes(day, point, civilTwilightAltitude); polar == "" {
+		result.CivilDawn = dawn.In(day.Location()).Format(astroTimeLayout)
+		result.CivilDusk = dusk.In(day.Location()).Format(astroTimeLayout)
+	}
+
+	phase := moonPhase(time.Date(day.Year(), day.Month(), day.Day(), 12, 0, 0, 0, day.Location()))
+	result.MoonPhase = moonPhases[int(phase*8+0.5)%8]
+	result.MoonIllumination = int(math.Round((1 - math.Cos(2*math.Pi*phase)) / 2 * 100))
+
+	return result, nil
+}
+
+// ----------------------------------------------------------------------------
+// moments when sun center crosses altitude in calendar day of date (sunrise equation),
+// polar is not empty if sun doesn't cross it
+func sunTimes(date time.Time, point geoPoint, altitude float64) (rise, set time.Time, polar string) {
+	rad := math.Pi / 180
+	day := time.Date(date.Year(), date.Month(), date.Day(), 12, 0, 0, 0, time.UTC)
+	meanSolarTime := julianDay(day) - julianDayJ2000 - point.Lon/360
+
+	anomaly := math.Mod(357.5291+0.98560028*meanSolarTime, 360) * rad
+	center := 1.9148*math.Sin(anomaly) + 0.02*math.Sin(2*anomaly) + 0.0003*math.Sin(3*anomaly)
+	longitude := math.Mod(anomaly/rad+center+180+102.9372, 360) * rad
+	transit := julianDayJ2000 + meanSolarTime + 0.0053*math.Sin(anomaly) - 0.0069*math.Sin(2*longitude)
+	declination := math.Asin(math.Sin(longitude) * math.Sin(23.4397*rad))
+
+	latitude := point.Lat * rad
+	cosHourAngle := (math.Sin(altitude*rad) - math.Sin(latitude)*math.Sin(declination)) /
+		(math.Cos(latitude) * math.Cos(declination))
+	switch {
+	case cosHourAngle > 1:
+		return time.Time{}, time.Time{}, polarNight
+	case cosHourAngle < -1:
+		return time.Time{}, time.Time{}, polarDay
+	}
+
+	hourAngle := math.Acos(cosHourAngle) / rad
+	return timeFromJulianDay(transit - hourAngle/360), timeFromJulianDay(transit + hourAngle/360), ""
+}
+
+// ----------------------------------------------------------------------------
+// moon phase from 0 (new moon) to 1, 0.5 - full moon
+func moonPhase(moment time.Time) float64 {
+	phase := math.Mod((julianDay(moment)-julianDayNewMoon)/synodicMonth, 1)
+	if phase < 0 {
+		phase++
+	}
+	return phase
+}
+
+// ----------------------------------------------------------------------------
+// julian day of moment
+func julianDay(moment time.Time) float64 {
+	return float64(moment.Unix())/86400 + julianDayUnixEpoch
+}
+
+// ----------------------------------------------------------------------------
+// moment of julian day, rounded to minute
+func timeFromJulianDay(julianDay float64) time.Time {
+	return time.Unix(int64(math.Round((julianDay-julianDayUnixEpoch)*1440))*60, 0).UTC()
+}
+
+// ----------------------------------------------------------------------------
+// add sun and moon to forecast by days, if coordinates of city are known
+func addAstro(forecastNext []dayForecast, cfg config) {
+	point, ok := cfg.coordinates()
+	if !ok {
+		return
+	}
+
+	for i := range forecastNext {
+		if astro, err := computeAstro(forecastNext[i].Date, point, cfg); err == nil {
+			forecastNext[i].Astro = &astro
+		}
+	}
+}
+
+// ----------------------------------------------------------------------------
+// night flags of hours of forecast by hours, hours without date are from today,
+// hour is night if its middle is before sunrise or after sunset, nil if coordinates are unknown
+func (cfg config) nightHours(forecastByHours []hourTemp) []bool {
+	point, ok := cfg.coordinates()
+	if !ok {
+		return nil
+	}
+
+	result := make([]bool, len(forecastByHours))
+	for i, item := range forecastByHours {
+		date := item.Date
+		if date == "" {
+			date = cfg.today()
+		}
+		day, err := cfg.parseDate(date)
+		if err != nil {
+			continue
+		}
+
+		sunrise, sunset, polar := sunTimes(day, point, sunriseAltitude)
+		switch polar {
+		case polarNight:
+			result[i] = true
+		case "":
+			// compare time of day only, so hours after midnight use sunrise of the day
+			middle := item.Hour*60 + 30
+			result[i] = middle < minuteOfDay(sunrise.In(day.Location())) || middle >= minuteOfDay(sunset.In(day.Location()))
+		}
+	}
+
+	return result
+}
+
+// ----------------------------------------------------------------------------
+// row of hours numbers, 4 chars for hour, night hours are marked by nightMark without colors too: " 22☾"
+func hoursRow(forecastByHours []hourTemp, night []bool) string {
+	result := ""
+	for i, item := range forecastByHours {
+		mark := " "
+		if i < len(night) && night[i] {
+			mark = nightMark
+		}
+		result += fmt.Sprintf("%3d%s", item.Hour, mark)
+	}
+	return result
+}
+
+// ----------------------------------------------------------------------------
+// minutes from midnight
+func minuteOfDay(moment time.Time) int {
+	return moment.Hour()*60 + moment.Minute()
+}
+
+// ----------------------------------------------------------------------------
+// columns of sun and moon for row of table of forecast by days
+func (cfg config) renderAstro(astro *astroInfo) string {
+	if astro == nil {
+		return ""
+	}
+
+	twilight, sun := "-", "-"
+	if astro.CivilDawn != "" {
+		twilight = astro.CivilDawn + "-" + astro.CivilDusk
+	}
+	if astro.Sunrise != "" {
+		sun = astro.Sunrise + "-" + astro.Sunset
+	}
+
+	moon, _ := cfg.moonCell(astro.MoonPhase)
+	return fmt.Sprintf(" %-11s %-11s %5s %s", twilight, sun, astro.DayLength, moon)
+}
+
+// ----------------------------------------------------------------------------
+// moon phase for table with its display width: icon, or name if colors are off
+func (cfg config) moonCell(phase string) (string, int) {
+	if cfg.noColor {
+		name := cfg.moonPhaseName(phase)
+		return name, len([]rune(name))
+	}
+	return moonIcons[phase], moonIconWidth
+}
+
+// ----------------------------------------------------------------------------
+// width of astro columns for days, moon column is as wide as its header or the widest phase
+func (cfg config) astroWidth(forecastNext []dayForecast) int {
+	moonWidth := len([]rune(cfg.tr("moon")))
+	for _, day := range forecastNext {
+		if day.Astro == nil {
+			continue
+		}
+		if _, width := cfg.moonCell(day.Astro.MoonPhase); width > moonWidth {
+			moonWidth = width
+		}
+	}
+	return astroTableWidth + 1 + moonWidth
+}
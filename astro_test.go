@@ -0,0 +1,132 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_computeAstro(t *testing.T) {
+	moscow := geoPoint{Lat: 55.7558, Lon: 37.6173}
+	murmansk := geoPoint{Lat: 68.9585, Lon: 33.0827}
+
+	tests := []struct {
+		name  string
+		date  string
+		point geoPoint
+		want  astroInfo
+	}{
+		{
+			name:  "autumn in Moscow",
+			date:  "2021-10-23",
+			point: moscow,
+			want: astroInfo{
+				Sunrise: "07:16", Sunset: "17:11", CivilDawn: "06:38", CivilDusk: "17:49", DayLength: "9:55",
+				MoonPhase: "waning_gibbous", MoonIllumination: 95,
+			},
+		},
+		{
+			name:  "full moon",
+			date:  "2021-10-20",
+			point: moscow,
+			want: astroInfo{
+				Sunrise: "07:10", Sunset: "17:18", CivilDawn: "06:32", CivilDusk: "17:56", DayLength: "10:08",
+				MoonPhase: "full_moon", MoonIllumination: 99,
+			},
+		},
+		{
+			name:  "polar night",
+			date:  "2021-12-21",
+			point: murmansk,
+			want: astroInfo{
+				CivilDawn: "10:22", CivilDusk: "15:09", DayLength: "0:00", Polar: polarNight,
+				MoonPhase: "waning_gibbous", MoonIllumination: 95,
+			},
+		},
+		{
+			name:  "polar day",
+			date:  "2021-06-21",
+			point: murmansk,
+			want: astroInfo{
+				DayLength: "24:00", Polar: polarDay,
+				MoonPhase: "waxing_gibbous", MoonIllumination: 85,
+			},
+		},
+	}
+
+	cfg := defaultConfig()
+	cfg.timeZone = time.FixedZone("MSK", 3*3600)
+	for _, tt := range tests {
+		got, err := computeAstro(tt.date, tt.point, cfg)
+		if err != nil {
+			t.Errorf("%q. computeAstro() error = %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q. computeAstro() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func Test_moonPhase(t *testing.T) {
+	tests := []struct {
+		moment time.Time
+		want   string
+	}{
+		{time.Date(2021, 10, 20, 15, 0, 0, 0, time.UTC), "full_moon"},
+		{time.Date(2021, 10, 28, 20, 0, 0, 0, time.UTC), "last_quarter"},
+		{time.Date(2021, 11, 4, 21, 0, 0, 0, time.UTC), "new_moon"},
+		{time.Date(2021, 11, 11, 12, 0, 0, 0, time.UTC), "first_quarter"},
+		{time.Date(2021, 11, 8, 12, 0, 0, 0, time.UTC), "waxing_crescent"},
+	}
+
+	for _, tt := range tests {
+		if got := moonPhases[int(moonPhase(tt.moment)*8+0.5)%8]; got != tt.want {
+			t.Errorf("moonPhase(%s) = %q, want %q", tt.moment, got, tt.want)
+		}
+	}
+}
+
+func Test_nightHours(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.city = "moscow"
+	cfg.timeZone = time.FixedZone("MSK", 3*3600)
+	cfg.clock = func() time.Time { return time.Date(2021, 10, 22, 15, 0, 0, 0, cfg.timeZone) }
+
+	hours := []hourTemp{{Hour: 6}, {Hour: 8}, {Hour: 12}, {Hour: 16}, {Hour: 17}, {Hour: 23}, {Date: "2021-10-23", Hour: 7}}
+	want := []bool{true, false, false, false, true, true, false}
+	if got := cfg.nightHours(hours); !reflect.DeepEqual(got, want) {
+		t.Errorf("nightHours() = %v, want %v", got, want)
+	}
+
+	cfg.city = "some-village"
+	if got := cfg.nightHours(hours); got != nil {
+		t.Errorf("nightHours() for unknown city = %v, want nil", got)
+	}
+}
+
+func Test_renderAstroMoon(t *testing.T) {
+	astro := &astroInfo{CivilDawn: "06:38", CivilDusk: "17:49", Sunrise: "07:16", Sunset: "17:11", DayLength: "9:55", MoonPhase: "waning_gibbous"}
+	days := []dayForecast{{Astro: astro}}
+
+	tests := []struct {
+		name      string
+		noColor   bool
+		want      string
+		wantWidth int
+	}{
+		{name: "icon", want: " 06:38-17:49 07:16-17:11  9:55 🌖", wantWidth: astroTableWidth + 1 + 4},
+		{name: "name without colors", noColor: true, want: " 06:38-17:49 07:16-17:11  9:55 waning gibbous", wantWidth: astroTableWidth + 1 + 14},
+	}
+
+	for _, tt := range tests {
+		cfg := defaultConfig()
+		cfg.lang, cfg.noColor = langEn, tt.noColor
+		if got := cfg.renderAstro(astro); got != tt.want {
+			t.Errorf("%q. renderAstro() = %q, want %q", tt.name, got, tt.want)
+		}
+		if got := cfg.astroWidth(days); got != tt.wantWidth {
+			t.Errorf("%q. astroWidth() = %d, want %d", tt.name, got, tt.wantWidth)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+// output for status bars: Waybar custom module JSON, streaming i3bar protocol and Polybar
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+)
+
+// barIntervalDefault - refresh interval of i3bar if -interval is not set, i3bar needs a stream
+const barIntervalDefault = 10 * time.Minute
+
+// barFormats - formats of status bars for -format option
+var barFormats = []string{formatWaybar, formatI3bar, formatPolybar}
+
+// tempBands - upper bounds of temperature bands, °C, for CSS class of Waybar
+var tempBands = []struct {
+	name  string
+	below int
+}{
+	{"frost", -10},
+	{"cold", 0},
+	{"cool", 10},
+	{"mild", 20},
+	{"warm", 30},
+	{"hot", math.MaxInt32},
+}
+
+// markupEscaper - escape text for Pango markup of Waybar
+var markupEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// waybarOutput - output of Waybar custom module with "return-type": "json"
+type waybarOutput struct {
+	Text       string   `json:"text"`
+	Tooltip    string   `json:"tooltip"`
+	Class      []string `json:"class"`
+	Percentage int      `json:"percentage"`
+}
+
+// i3barBlock - block of i3bar protocol
+type i3barBlock struct {
+	Name      string `json:"name"`
+	Instance  string `json:"instance,omitempty"`
+	FullText  string `json:"full_text"`
+	ShortText string `json:"short_text"`
+	Color     string `json:"color,omitempty"`
+	Urgent    bool   `json:"urgent,omitempty"`
+}
+
+// ----------------------------------------------------------------------------
+// bars refresh often: cached pages and network deadline as for -oneline, i3bar is always a stream,
+// cache TTL by default is half of interval at most, so bar is not late by two intervals
+func setupBar(cfg *config) {
+	setupCache(cfg)
+	if cfg.format == formatI3bar && cfg.interval <= 0 {
+		cfg.interval = barIntervalDefault
+	}
+	if _, ok := cfg.sources[configKey("cache-ttl")]; !ok && cfg.interval > 0 && cfg.cacheTTL > cfg.interval/2 {
+		cfg.cacheTTL = cfg.interval / 2
+	}
+}
+
+// ----------------------------------------------------------------------------
+// band of temperature in units from config: "frost", "cold", "cool", "mild", "warm" or "hot"
+func (cfg config) tempBand(temp int) string {
+	celsius := cfg.toCelsius(float64(temp))
+
+	for _, band := range tempBands {
+		if celsius < float64(band.below) {
+			return band.name
+		}
+	}
+	return tempBands[len(tempBands)-1].name
+}
+
+// ----------------------------------------------------------------------------
+// temperature on scale of theme gradient, 0..100
+func (cfg config) tempPercentage(temp int) int {
+	celsius := cfg.toCelsius(float64(temp))
+
+	result := int(math.Round((celsius - gradientTempMin) / (gradientTempMax - gradientTempMin) * 100))
+	if result < 0 {
+		return 0
+	}
+	if result > 100 {
+		return 100
+	}
+	return result
+}
+
+// ----------------------------------------------------------------------------
+// full text tables without colors, for tooltip
+func renderTooltip(forecastNow map[string]interface{}, forecastByHours []hourTemp, forecastNext []dayForecast, cfg config) string {
+	cfg.noColor, cfg.terminal = true, terminalCaps{}
+	buf := bytes.Buffer{}
+	renderText(terminalWriter{writer: &buf}, forecastNow, forecastByHours, forecastNext, cfg)
+	return strings.TrimRight(buf.String(), "\n")
+}
+
+// ----------------------------------------------------------------------------
+// one update of status bar in format from config, text is rendered by -template
+func renderBar(forecastNow map[string]interface{}, forecastByHours []hourTemp, forecastNext []dayForecast, cfg config) string {
+	text := renderOneline(forecastNow, cfg)
+	desc, _ := forecastNow["desc_now"].(string)
+	condition := weatherCondition(desc)
+	warnings, _ := forecastNow["warnings"].([]weatherWarning)
+	temp, hasTemp := forecastNow["term_now"].(int)
+
+	color := ""
+	if hasTemp && strings.HasPrefix(cfg.tempColor(float64(temp)), "#") {
+		color = cfg.tempColor(float64(temp))
+	}
+
+	switch cfg.format {
+	case formatWaybar:
+		output := waybarOutput{
+			Text:    markupEscaper.Replace(text),
+			Tooltip: markupEscaper.Replace(renderTooltip(forecastNow, forecastByHours, forecastNext, cfg)),
+			Class:   nonEmpty(condition),
+		}
+		if hasTemp {
+			output.Class = append(output.Class, cfg.tempBand(temp))
+			output.Percentage = cfg.tempPercentage(temp)
+		}
+		if len(warnings) > 0 {
+			output.Class = append(output.Class, "warning")
+		}
+		jsonBytes, _ := json.Marshal(output)
+		return string(jsonBytes)
+
+	case formatI3bar:
+		block := i3barBlock{Name: appName, Instance: condition, FullText: text, Color: color, Urgent: len(warnings) > 0}
+		if hasTemp {
+			block.ShortText = fmt.Sprintf("%d°", temp)
+		}
+		jsonBytes, _ := json.Marshal([]i3barBlock{block})
+		return string(jsonBytes) + ","
+
+	default:
+		if color == "" {
+			return text
+		}
+		return "%{F" + color + "}" + text + "%{F-}"
+	}
+}
+
+// ----------------------------------------------------------------------------
+// block for status bar when weather can't be got and there is no previous update
+func renderBarError(err error, cfg config) string {
+	message := strings.SplitN(err.Error(), "\n", 2)[0]
+
+	switch cfg.format {
+	case formatWaybar:
+		jsonBytes, _ := json.Marshal(waybarOutput{Text: "⚠", Tooltip: markupEscaper.Replace(err.Error()), Class: []string{"error"}})
+		return string(jsonBytes)
+
+	case formatI3bar:
+		jsonBytes, _ := json.Marshal([]i3barBlock{{Name: appName, FullText: "⚠ " + message, ShortText: "⚠", Urgent: true}})
+		return string(jsonBytes) + ","
+
+	default:
+		return "⚠"
+	}
+}
+
+// ----------------------------------------------------------------------------
+// one update of status bar, error if weather can't be got
+func barUpdate(cfg *config) (string, error) {
+	forecastNow, forecastByHours, forecastNext, err := fetchWeather(cfg)
+	if err != nil {
+		return "", err
+	}
+	if err := cityNotFoundError(forecastNow, *cfg); err != nil {
+		return "", err
+	}
+
+	convertForecastUnits(forecastNow, forecastByHours, forecastNext, cfg.units)
+	return renderBar(forecastNow, forecastByHours, forecastNext, *cfg), nil
+}
+
+// ----------------------------------------------------------------------------
+// print status bar updates, on interval if it is set, i3bar protocol starts with header,
+// on errors the stream is not broken: the last update is kept or an error block is printed
+func runBar(cfg config) int {
+	if cfg.format == formatI3bar {
+		fmt.Println(`{"version":1}`)
+		fmt.Println("[")
+	}
+
+	updated := false
+	for {
+		line, err := barUpdate(&cfg)
+		switch {
+		case err == nil:
+			fmt.Println(line)
+			updated = true
+		case !updated:
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Println(renderBarError(err, cfg))
+		default:
+			fmt.Fprintln(os.Stderr, err)
+		}
+
+		if cfg.interval <= 0 {
+			if err != nil {
+				return 1
+			}
+			return 0
+		}
+		time.Sleep(cfg.interval)
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_tempBand(t *testing.T) {
+	tests := []struct {
+		temp  int
+		units string
+		band  string
+		perc  int
+	}{
+		{temp: -25, units: unitsCelsius, band: "frost", perc: 7},
+		{temp: -10, units: unitsCelsius, band: "cold", perc: 29},
+		{temp: 0, units: unitsCelsius, band: "cool", perc: 43},
+		{temp: 12, units: unitsCelsius, band: "mild", perc: 60},
+		{temp: 25, units: unitsCelsius, band: "warm", perc: 79},
+		{temp: 45, units: unitsCelsius, band: "hot", perc: 100},
+		{temp: -40, units: unitsCelsius, band: "frost", perc: 0},
+		{temp: 54, units: unitsFahrenheit, band: "mild", perc: 60},
+		{temp: 32, units: unitsFahrenheit, band: "cool", perc: 43},
+	}
+
+	for _, tt := range tests {
+		cfg := defaultConfig()
+		cfg.units = tt.units
+		if got := cfg.tempBand(tt.temp); got != tt.band {
+			t.Errorf("tempBand(%d%s) = %q, want %q", tt.temp, tt.units, got, tt.band)
+		}
+		if got := cfg.tempPercentage(tt.temp); got != tt.perc {
+			t.Errorf("tempPercentage(%d%s) = %d, want %d", tt.temp, tt.units, got, tt.perc)
+		}
+	}
+}
+
+func Test_renderBar(t *testing.T) {
+	forecastNow := map[string]interface{}{
+		"city":     "Погода в Москве на 10 дней — Яндекс Погода",
+		"term_now": 12,
+		"desc_now": "Облачно с прояснениями",
+		"wind":     "3 м/с, СЗ",
+		"humidity": "71%",
+		"pressure": "745 мм рт. ст.",
+		"warnings": []weatherWarning{{Severity: severityYellow, Text: "Сильный ветер"}},
+	}
+	forecastByHours := []hourTemp{{Hour: 15, Temp: 12}, {Hour: 16, Temp: 11, Icon: "icon_rain"}}
+	forecastNext := []dayForecast{{DateHuman: "23.10 (сб)", Date: "2021-10-23", Desc: "дождь", Temp: 9, TempNight: 4}}
+
+	cfg := defaultConfig()
+	cfg.city, cfg.colorDepth, cfg.template = "moscow", colorDepthTrue, "{icon} {temp}° <{city}>"
+	cfg.timeZone = time.UTC
+	cfg.clock = func() time.Time { return time.Date(2021, 10, 22, 15, 0, 0, 0, time.UTC) }
+
+	cfg.format = formatWaybar
+	var waybar waybarOutput
+	if err := json.Unmarshal([]byte(renderBar(forecastNow, forecastByHours, forecastNext, cfg)), &waybar); err != nil {
+		t.Fatal(err)
+	}
+	if waybar.Text != "⛅ 12° &lt;Москва&gt;" || waybar.Percentage != 60 ||
+		strings.Join(waybar.Class, " ") != "partly-cloudy mild warning" {
+		t.Errorf("renderBar() waybar = %+v", waybar)
+	}
+	for _, want := range []string{"Сейчас: 12 °C - Облачно с прояснениями", " 15☾ 16☾", " 23.10 (сб)   9° дождь"} {
+		if !strings.Contains(waybar.Tooltip, want) {
+			t.Errorf("renderBar() waybar tooltip has no %q:\n%s", want, waybar.Tooltip)
+		}
+	}
+	if strings.Contains(waybar.Tooltip, "\x1b[") || strings.HasSuffix(waybar.Tooltip, "\n") {
+		t.Errorf("renderBar() waybar tooltip has colors or trailing newline: %q", waybar.Tooltip)
+	}
+
+	cfg.format = formatI3bar
+	line := renderBar(forecastNow, forecastByHours, forecastNext, cfg)
+	var blocks []i3barBlock
+	if err := json.Unmarshal([]byte(strings.TrimSuffix(line, ",")), &blocks); err != nil || !strings.HasSuffix(line, ",") {
+		t.Fatalf("renderBar() i3bar = %q, %v", line, err)
+	}
+	if len(blocks) != 1 || blocks[0].FullText != "⛅ 12° <Москва>" || blocks[0].ShortText != "12°" ||
+		blocks[0].Instance != "partly-cloudy" || !blocks[0].Urgent || !strings.HasPrefix(blocks[0].Color, "#") {
+		t.Errorf("renderBar() i3bar = %+v", blocks)
+	}
+
+	cfg.format = formatPolybar
+	if got := renderBar(forecastNow, forecastByHours, forecastNext, cfg); got != "%{F"+blocks[0].Color+"}⛅ 12° <Москва>%{F-}" {
+		t.Errorf("renderBar() polybar = %q", got)
+	}
+}
+
+func Test_setupBar(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.format = formatI3bar
+	setupBar(&cfg)
+	if cfg.interval != barIntervalDefault || cfg.cacheTTL != barIntervalDefault/2 || cfg.deadline != onelineDeadline {
+		t.Errorf("setupBar() i3bar = %v, %v, %v", cfg.interval, cfg.cacheTTL, cfg.deadline)
+	}
+
+	cfg = defaultConfig()
+	cfg.format, cfg.interval, cfg.cacheTTL = formatPolybar, time.Hour, 30*time.Minute
+	cfg.sources = map[string]string{"cache_ttl": sourceFlag}
+	setupBar(&cfg)
+	if cfg.cacheTTL != 30*time.Minute {
+		t.Errorf("setupBar() with -cache-ttl = %v, want 30m", cfg.cacheTTL)
+	}
+
+	cfg = defaultConfig()
+	cfg.format = formatWaybar
+	setupBar(&cfg)
+	if cfg.interval != 0 || cfg.cacheTTL != onelineCacheTTL || cfg.noToday {
+		t.Errorf("setupBar() waybar = %v, %v, %v", cfg.interval, cfg.cacheTTL, cfg.noToday)
+	}
+}
+
+func Test_barUpdate(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.city, cfg.format, cfg.noToday = "moscow", formatPolybar, true
+	cfg.page = "testdata/moscow.html"
+	if line, err := barUpdate(&cfg); err != nil || !strings.Contains(line, "12°") {
+		t.Errorf("barUpdate() = %q, %v", line, err)
+	}
+
+	cfg.page = "testdata/not-exists.html"
+	if line, err := barUpdate(&cfg); err == nil || line != "" {
+		t.Errorf("barUpdate() with missing page = %q, %v, want error", line, err)
+	}
+}
+
+func Test_renderBarError(t *testing.T) {
+	err := errors.New("City \"mosсow\" not found\nDid you mean: moscow?")
+	cfg := defaultConfig()
+
+	cfg.format = formatWaybar
+	var waybar waybarOutput
+	if jsonErr := json.Unmarshal([]byte(renderBarError(err, cfg)), &waybar); jsonErr != nil {
+		t.Fatal(jsonErr)
+	}
+	if waybar.Text != "⚠" || waybar.Tooltip != err.Error() || strings.Join(waybar.Class, " ") != "error" {
+		t.Errorf("renderBarError() waybar = %+v", waybar)
+	}
+
+	cfg.format = formatI3bar
+	line := renderBarError(err, cfg)
+	var blocks []i3barBlock
+	if jsonErr := json.Unmarshal([]byte(strings.TrimSuffix(line, ",")), &blocks); jsonErr != nil || !strings.HasSuffix(line, ",") {
+		t.Fatalf("renderBarError() i3bar = %q, %v", line, jsonErr)
+	}
+	if len(blocks) != 1 || blocks[0].FullText != "⚠ City \"mosсow\" not found" || !blocks[0].Urgent {
+		t.Errorf("renderBarError() i3bar = %+v", blocks)
+	}
+
+	cfg.format = formatPolybar
+	if got := renderBarError(err, cfg); got != "⚠" {
+		t.Errorf("renderBarError() polybar = %q", got)
+	}
+}
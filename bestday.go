@@ -0,0 +1,227 @@
+// "best-day" command: rank days of forecast by scoring profile
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// maximum points of criteria of scoring profile
+const (
+	scoreTempMax    = 40
+	scorePrecipMax  = 30
+	scoreWindMax    = 20
+	scoreWeekendMax = 10
+	// scoreTempStep - points for each degree out of temperature range
+	scoreTempStep = 5
+	// scoreWindStep - points for each m/s over wind limit
+	scoreWindStep = 5
+)
+
+// rePrecipDesc - description of day with snow or hail, rain is found by reRainyDesc
+var rePrecipDesc = regexp.MustCompile(`(?i)снег|snow|sleet|град|hail`)
+
+// dayScore - score of day of forecast for city
+type dayScore struct {
+	City      string           `json:"city"`
+	Date      string           `json:"date"`
+	DateHuman string           `json:"-"`
+	Desc      string           `json:"desc"`
+	Score     int              `json:"score"`
+	MaxScore  int              `json:"max_score"`
+	Criteria  []criterionScore `json:"criteria"`
+}
+
+// criterionScore - points of day by one criterion of scoring profile, with explanation
+type criterionScore struct {
+	Name  string `json:"name"` // temp, precip, wind or weekend
+	Score int    `json:"score"`
+	Max   int    `json:"max"`
+	Text  string `json:"text"`
+}
+
+// ----------------------------------------------------------------------------
+// score day by profile from options: temperature range, no precipitation, wind limit and weekend,
+// temperatures of day and range are in °C, explanation is in units from config
+func scoreDay(day dayForecast, city string, cfg config) dayScore {
+	result := dayScore{City: city, Date: day.Date, DateHuman: day.DateHuman, Desc: day.Desc}
+
+	temp, tempMin, tempMax := convertTemp(day.Temp, cfg.units), convertTemp(cfg.tempMin, cfg.units), convertTemp(cfg.tempMax, cfg.units)
+	tempScore := criterionScore{Name: "temp", Score: scoreTempMax, Max: scoreTempMax}
+	switch {
+	case day.Temp < cfg.tempMin:
+		tempScore.Score = maxInt(0, scoreTempMax-scoreTempStep*(cfg.tempMin-day.Temp))
+		tempScore.Text = fmt.Sprintf(cfg.tr("score_temp_below"), temp, tempMin-temp, tempMin, tempMax)
+	case day.Temp > cfg.tempMax:
+		tempScore.Score = maxInt(0, scoreTempMax-scoreTempStep*(day.Temp-cfg.tempMax))
+		tempScore.Text = fmt.Sprintf(cfg.tr("score_temp_above"), temp, temp-tempMax, tempMin, tempMax)
+	default:
+		tempScore.Text = fmt.Sprintf(cfg.tr("score_temp_in"), temp, tempMin, tempMax)
+	}
+	result.Criteria = append(result.Criteria, tempScore)
+
+	if cfg.dry {
+		precipScore := criterionScore{Name: "precip", Score: scorePrecipMax, Max: scorePrecipMax, Text: fmt.Sprintf(cfg.tr("score_dry"), day.Desc)}
+		if isRainyDay(day) || rePrecipDesc.MatchString(day.Desc) {
+			precipScore.Score, precipScore.Text = 0, fmt.Sprintf(cfg.tr("score_precip"), day.Desc)
+		}
+		result.Criteria = append(result.Criteria, precipScore)
+	}
+
+	windScore := criterionScore{Name: "wind", Score: scoreWindMax / 2, Max: scoreWindMax, Text: cfg.tr("score_wind_unknown")}
+	if wind, ok := maxWind(day.Parts); ok {
+		windScore.Score = scoreWindMax
+		windScore.Text = fmt.Sprintf(cfg.tr("score_wind_ok"), formatAmount(wind), cfg.windMax)
+		if wind > float64(cfg.windMax) {
+			windScore.Score = maxInt(0, scoreWindMax-int(float64(scoreWindStep)*(wind-float64(cfg.windMax))+0.5))
+			windScore.Text = fmt.Sprintf(cfg.tr("score_wind_over"), formatAmount(wind), cfg.windMax)
+		}
+	}
+	result.Criteria = append(result.Criteria, windScore)
+
+	if cfg.preferWeekends {
+		weekendScore := criterionScore{Name: "weekend", Max: scoreWeekendMax, Text: cfg.tr("score_weekday")}
+		if date, err := cfg.parseDate(day.Date); err == nil && (date.Weekday() == time.Saturday || date.Weekday() == time.Sunday) {
+			weekendScore.Score, weekendScore.Text = scoreWeekendMax, cfg.tr("score_weekend")
+		}
+		result.Criteria = append(result.Criteria, weekendScore)
+	}
+
+	for _, criterion := range result.Criteria {
+		result.Score += criterion.Score
+		result.MaxScore += criterion.Max
+	}
+
+	return result
+}
+
+// ----------------------------------------------------------------------------
+// maximum wind speed of day parts, false if there are no day parts with wind
+func maxWind(parts []dayPart) (float64, bool) {
+	result, found := 0.0, false
+	for _, part := range parts {
+		if wind, ok := parseNumber(part.Wind); ok {
+			if !found || wind > result {
+				result = wind
+			}
+			found = true
+		}
+	}
+	return result, found
+}
+
+// ----------------------------------------------------------------------------
+// maximum of two ints
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// ----------------------------------------------------------------------------
+// sort days by score, then by date, order of cities is kept for same date
+func rankDays(scores []dayScore) {
+	sort.SliceStable(scores, func(i, j int) bool {
+		if scores[i].Score != scores[j].Score {
+			return scores[i].Score > scores[j].Score
+		}
+		return scores[i].Date < scores[j].Date
+	})
+}
+
+// ----------------------------------------------------------------------------
+// name of city for ranked list: from gazetteer in language of output, or as is
+func (cfg config) cityName(title string) string {
+	if cfg.location != nil {
+		return placeFromTitle(title)
+	}
+	if city, ok := lookupCity(cfg.city); ok {
+		if cfg.lang == langEn {
+			return city.Name
+		}
+		return city.NameRu
+	}
+	if cfg.city == "" {
+		return placeFromTitle(title)
+	}
+	return cfg.city
+}
+
+// ----------------------------------------------------------------------------
+// ranked list with points of each criterion
+func renderBestDays(scores []dayScore, cfg config) []string {
+	cityWidth := 0
+	for _, score := range scores {
+		if len([]rune(score.City)) > cityWidth {
+			cityWidth = len([]rune(score.City))
+		}
+	}
+
+	result := []string{}
+	for i, score := range scores {
+		date := cfg.weekendRe().ReplaceAllString(score.DateHuman, "<weekend>$1</>")
+		result = append(result, fmt.Sprintf("%2d. %s %-*s <value>%3d</>/%d %s",
+			i+1, date, cityWidth, score.City, score.Score, score.MaxScore, score.Desc))
+		for _, criterion := range score.Criteria {
+			mark := "~"
+			switch criterion.Score {
+			case criterion.Max:
+				mark = "+"
+			case 0:
+				mark = "-"
+			}
+			result = append(result, fmt.Sprintf("    %s %2d/%d %s", mark, criterion.Score, criterion.Max, criterion.Text))
+		}
+	}
+
+	return result
+}
+
+// ----------------------------------------------------------------------------
+// "best-day" command, for cities from args or city from options
+func runBestDay(cfg config, args []string) int {
+	cities := args
+	if len(cities) == 0 {
+		cities = []string{cfg.city}
+	}
+
+	scores := []dayScore{}
+	for _, city := range cities {
+		cityCfg := cfg
+		if len(args) > 0 {
+			cityCfg.city, cityCfg.lat, cityCfg.lon = resolveCity(city), "", ""
+			if err := validateConfig(&cityCfg); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 1
+			}
+		}
+		cityCfg.noToday, cityCfg.detailed = true, true
+
+		// forecast is scored in °C as -temp-min and -temp-max
+		forecastNow, _, forecastNext := getWeather(&cityCfg)
+		exitIfCityNotFound(forecastNow, cityCfg)
+
+		title, _ := forecastNow["city"].(string)
+		for _, day := range forecastNext {
+			scores = append(scores, scoreDay(day, cityCfg.cityName(title), cityCfg))
+		}
+	}
+	rankDays(scores)
+
+	if cfg.getJSON {
+		jsonBytes, _ := json.Marshal(scores)
+		fmt.Println(string(jsonBytes))
+		return 0
+	}
+
+	outWriter := getColorWriter(cfg.terminal)
+	for _, line := range renderBestDays(scores, cfg) {
+		outWriter.Println(cfg.ansiColourString(line))
+	}
+	return 0
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_scoreDay(t *testing.T) {
+	windParts := func(winds ...string) []dayPart {
+		parts := []dayPart{}
+		for _, wind := range winds {
+			parts = append(parts, dayPart{Wind: wind})
+		}
+		return parts
+	}
+
+	tests := []struct {
+		name           string
+		day            dayForecast
+		preferWeekends bool
+		wantScore      int
+		wantMax        int
+		wantCriteria   []int
+	}{
+		{
+			name:         "perfect weekday",
+			day:          dayForecast{Date: "2021-10-22", Temp: 20, Desc: "ясно", Parts: windParts("3 м/с", "5 м/с")},
+			wantScore:    90,
+			wantMax:      90,
+			wantCriteria: []int{40, 30, 20},
+		},
+		{
+			name:           "cold rainy weekend with strong wind",
+			day:            dayForecast{Date: "2021-10-23", Temp: 12, Desc: "небольшой дождь", Parts: windParts("6 м/с", "10 м/с")},
+			preferWeekends: true,
+			wantScore:      45,
+			wantMax:        100,
+			wantCriteria:   []int{25, 0, 10, 10},
+		},
+		{
+			name:         "hot day without wind",
+			day:          dayForecast{Date: "2021-10-25", Temp: 28, Desc: "облачно"},
+			wantScore:    65,
+			wantMax:      90,
+			wantCriteria: []int{25, 30, 10},
+		},
+	}
+
+	for _, tt := range tests {
+		cfg := defaultConfig()
+		cfg.lang = langEn
+		cfg.timeZone = time.UTC
+		cfg.preferWeekends = tt.preferWeekends
+
+		got := scoreDay(tt.day, "Moscow", cfg)
+		criteria := []int{}
+		for _, criterion := range got.Criteria {
+			criteria = append(criteria, criterion.Score)
+		}
+		if got.Score != tt.wantScore || got.MaxScore != tt.wantMax || !reflect.DeepEqual(criteria, tt.wantCriteria) {
+			t.Errorf("%q. scoreDay() = %d/%d %v, want %d/%d %v", tt.name, got.Score, got.MaxScore, criteria, tt.wantScore, tt.wantMax, tt.wantCriteria)
+		}
+	}
+}
+
+func Test_scoreDayUnits(t *testing.T) {
+	day := dayForecast{Date: "2021-10-22", Temp: 12, Desc: "ясно"}
+	tests := []struct {
+		units     string
+		wantScore int
+		wantText  string
+	}{
+		{units: unitsCelsius, wantScore: 25, wantText: "temperature 12°, 3° below 15..25°"},
+		{units: unitsFahrenheit, wantScore: 25, wantText: "temperature 54°, 5° below 59..77°"},
+	}
+
+	for _, tt := range tests {
+		cfg := defaultConfig()
+		cfg.lang, cfg.units = langEn, tt.units
+		got := scoreDay(day, "Moscow", cfg).Criteria[0]
+		if got.Score != tt.wantScore || got.Text != tt.wantText {
+			t.Errorf("scoreDay() with units %s = %d %q, want %d %q", tt.units, got.Score, got.Text, tt.wantScore, tt.wantText)
+		}
+	}
+}
+
+func Test_rankDays(t *testing.T) {
+	scores := []dayScore{
+		{City: "Moscow", Date: "2021-10-24", Score: 50},
+		{City: "Moscow", Date: "2021-10-23", Score: 70},
+		{City: "Istra", Date: "2021-10-24", Score: 70},
+		{City: "Istra", Date: "2021-10-23", Score: 70},
+	}
+	rankDays(scores)
+
+	got := []string{}
+	for _, score := range scores {
+		got = append(got, score.City+" "+score.Date)
+	}
+	want := []string{"Moscow 2021-10-23", "Istra 2021-10-23", "Istra 2021-10-24", "Moscow 2021-10-24"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rankDays() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,192 @@
+// cache of yandex pages in user cache directory
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/msoap/html2data"
+	"golang.org/x/net/html/charset"
+)
+
+// cacheFileExt - extension of cached pages
+const cacheFileExt = ".html"
+
+// errPageNotFound - page is downloaded with 404 status, it is parsed as page without forecast, not cached
+var errPageNotFound = errors.New("page not found")
+
+// ----------------------------------------------------------------------------
+// get html document from saved page if it is set, by URL otherwise
+func getPageDoc(ctx context.Context, savedPage, pageURL string, cfg config) html2data.Doc {
+	if savedPage != "" {
+		return html2data.FromFile(savedPage)
+	}
+	return getDoc(ctx, pageURL, cfg)
+}
+
+// ----------------------------------------------------------------------------
+// context with cfg.deadline for all downloads of one run, without deadline if it is 0
+func deadlineContext(cfg config) (context.Context, context.CancelFunc) {
+	if cfg.deadline <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), cfg.deadline)
+}
+
+// ----------------------------------------------------------------------------
+// get html document by URL, from cache if it is not older than cfg.cacheTTL,
+// with -as-of option only cached page of any age is used, it is replay of the past,
+// if download fails or ctx is done by cfg.deadline cached page of any age is used too
+func getDoc(ctx context.Context, pageURL string, cfg config) html2data.Doc {
+	if cfg.asOf != "" {
+		if cacheFile, err := cachePath(pageURL); err == nil {
+			if content, ok := readCache(cacheFile, time.Duration(math.MaxInt64), cfg.now()); ok {
+				return html2data.FromReader(bytes.NewReader(content))
+			}
+		}
+		return html2data.Doc{Err: fmt.Errorf("no cached page %s for -as-of, use -page, -page-mini and -page-details options for saved pages", pageURL)}
+	}
+
+	ttl := cfg.cacheTTL
+	if ttl <= 0 && cfg.deadline <= 0 {
+		return html2data.FromURL(pageURL, html2data.URLCfg{UA: userAgent})
+	}
+
+	cacheFile := ""
+	if ttl > 0 {
+		if path, err := cachePath(pageURL); err == nil {
+			cacheFile = path
+			if content, ok := readCache(cacheFile, ttl, cfg.now()); ok {
+				return html2data.FromReader(bytes.NewReader(content))
+			}
+		}
+	}
+
+	content, err := downloadPage(ctx, pageURL)
+	if err == errPageNotFound {
+		// unknown city, as with download without cache
+		return html2data.FromReader(bytes.NewReader(content))
+	}
+	if err != nil {
+		if cacheFile != "" && cfg.deadline > 0 {
+			if content, ok := readCache(cacheFile, time.Duration(math.MaxInt64), cfg.now()); ok {
+				return html2data.FromReader(bytes.NewReader(content))
+			}
+		}
+		return html2data.Doc{Err: err}
+	}
+
+	if cacheFile != "" {
+		if err := writeCache(cacheFile, content); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write cache: %s\n", err)
+		}
+	}
+
+	return html2data.FromReader(bytes.NewReader(content))
+}
+
+// ----------------------------------------------------------------------------
+// directory for cached pages
+func cacheDir() (string, error) {
+	userCacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(userCacheDir, appConfigDirName), nil
+}
+
+// ----------------------------------------------------------------------------
+// path of cached page for URL, file name is escaped URL
+func cachePath(pageURL string) (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, url.QueryEscape(pageURL)+cacheFileExt), nil
+}
+
+// ----------------------------------------------------------------------------
+// read cached page if it is not older than ttl at the moment now
+func readCache(cacheFile string, ttl time.Duration, now time.Time) ([]byte, bool) {
+	stat, err := os.Stat(cacheFile)
+	if err != nil || now.Sub(stat.ModTime()) > ttl {
+		return nil, false
+	}
+
+	content, err := ioutil.ReadFile(cacheFile)
+	if err != nil {
+		return nil, false
+	}
+
+	return content, true
+}
+
+// ----------------------------------------------------------------------------
+// save page to cache
+func writeCache(cacheFile string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(cacheFile), 0700); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(cacheFile, content, 0600)
+}
+
+// ----------------------------------------------------------------------------
+// download page by http(s) until ctx is done, convert to UTF-8,
+// page with 404 status is returned with errPageNotFound
+func downloadPage(ctx context.Context, pageURL string) ([]byte, error) {
+	cookie, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("User-Agent", userAgent)
+
+	response, err := (&http.Client{Jar: cookie}).Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := ioutil.ReadAll(response.Body)
+	if errClose := response.Body.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusNotFound {
+		return nil, fmt.Errorf("failed to get %s: %s", pageURL, response.Status)
+	}
+
+	if contentType := response.Header.Get("Content-Type"); contentType != "" {
+		reader, err := charset.NewReader(bytes.NewReader(content), contentType)
+		if err != nil {
+			return nil, err
+		}
+		if content, err = ioutil.ReadAll(reader); err != nil {
+			return nil, err
+		}
+	}
+
+	if response.StatusCode == http.StatusNotFound {
+		return content, errPageNotFound
+	}
+	return content, nil
+}
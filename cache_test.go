@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_readCache(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "yandex-weather-cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir) // nolint: errcheck
+
+	cacheFile := filepath.Join(tmpDir, "page.html")
+	if _, ok := readCache(cacheFile, time.Hour, time.Now()); ok {
+		t.Errorf("readCache() for missing file is ok")
+	}
+
+	if err := writeCache(cacheFile, []byte("html")); err != nil {
+		t.Fatal(err)
+	}
+	modTime := time.Date(2021, 10, 22, 15, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(cacheFile, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want bool
+	}{
+		{"fresh", modTime.Add(10 * time.Minute), true},
+		{"expired", modTime.Add(2 * time.Hour), false},
+		{"moment before download", modTime.Add(-time.Hour), true},
+	}
+
+	for _, tt := range tests {
+		content, ok := readCache(cacheFile, time.Hour, tt.now)
+		if ok != tt.want || ok && string(content) != "html" {
+			t.Errorf("%q. readCache() = %q, %v, want %v", tt.name, content, ok, tt.want)
+		}
+	}
+}
+
+func Test_getDocDeadline(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "yandex-weather-cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)                                     // nolint: errcheck
+	defer os.Setenv("XDG_CACHE_HOME", os.Getenv("XDG_CACHE_HOME")) // nolint: errcheck
+	if err := os.Setenv("XDG_CACHE_HOME", tmpDir); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(500 * time.Millisecond)
+		}
+		fmt.Fprint(w, "<title>fresh</title>")
+	}))
+	defer server.Close()
+
+	cfg := defaultConfig()
+	cfg.cacheTTL, cfg.deadline = time.Minute, 100*time.Millisecond
+	title := func(pageURL string) string {
+		ctx, cancel := deadlineContext(cfg)
+		defer cancel()
+		text, err := getDoc(ctx, pageURL, cfg).GetDataSingle("title")
+		if err != nil {
+			return "error: " + err.Error()
+		}
+		return text
+	}
+
+	if got := title(server.URL + "/slow"); !strings.HasPrefix(got, "error: ") {
+		t.Errorf("getDoc() after deadline without cache = %q, want error", got)
+	}
+
+	cacheFile, err := cachePath(server.URL + "/slow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writeCache(cacheFile, []byte("<title>stale</title>")); err != nil {
+		t.Fatal(err)
+	}
+	modTime := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(cacheFile, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+	if got := title(server.URL + "/slow"); got != "stale" {
+		t.Errorf("getDoc() after deadline with expired cache = %q, want stale", got)
+	}
+
+	if got := title(server.URL + "/fast"); got != "fresh" {
+		t.Errorf("getDoc() before deadline = %q, want fresh", got)
+	}
+	if cacheFile, err := cachePath(server.URL + "/fast"); err != nil {
+		t.Fatal(err)
+	} else if _, ok := readCache(cacheFile, time.Minute, time.Now()); !ok {
+		t.Errorf("getDoc() before deadline didn't write cache")
+	}
+}
+
+func Test_getDocAsOf(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "yandex-weather-cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)                                     // nolint: errcheck
+	defer os.Setenv("XDG_CACHE_HOME", os.Getenv("XDG_CACHE_HOME")) // nolint: errcheck
+	if err := os.Setenv("XDG_CACHE_HOME", tmpDir); err != nil {
+		t.Fatal(err)
+	}
+
+	downloads := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downloads++
+		fmt.Fprint(w, "<title>today</title>")
+	}))
+	defer server.Close()
+
+	cfg := defaultConfig()
+	cfg.asOf = "2021-10-22 15:00"
+	if _, err := getDoc(context.Background(), server.URL+"/moscow", cfg).GetDataSingle("title"); err == nil || !strings.Contains(err.Error(), "-as-of") || downloads > 0 {
+		t.Errorf("getDoc() with -as-of without cache = %v, %d downloads, want error without download", err, downloads)
+	}
+
+	cacheFile, err := cachePath(server.URL + "/moscow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writeCache(cacheFile, []byte("<title>saved</title>")); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := getDoc(context.Background(), server.URL+"/moscow", cfg).GetDataSingle("title"); err != nil || got != "saved" || downloads > 0 {
+		t.Errorf("getDoc() with -as-of = %q, %v, %d downloads, want cached page", got, err, downloads)
+	}
+}
+
+func Test_getDocNotFound(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "yandex-weather-cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)                                     // nolint: errcheck
+	defer os.Setenv("XDG_CACHE_HOME", os.Getenv("XDG_CACHE_HOME")) // nolint: errcheck
+	if err := os.Setenv("XDG_CACHE_HOME", tmpDir); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, "<body><h1>not found</h1></body>")
+	}))
+	defer server.Close()
+
+	cfg := defaultConfig()
+	cfg.cacheTTL, cfg.deadline = time.Minute, time.Second
+	doc := getDoc(context.Background(), server.URL+"/londn", cfg)
+	if got, err := doc.GetDataSingle("h1"); err != nil || got != "not found" {
+		t.Errorf("getDoc() for 404 = %q, %v, want page for city not found", got, err)
+	}
+
+	cacheFile, err := cachePath(server.URL + "/londn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := readCache(cacheFile, time.Minute, time.Now()); ok {
+		t.Errorf("getDoc() cached page with 404")
+	}
+
+	cfg.city, cfg.noToday, cfg.baseURL, cfg.timeZone = "londn", true, server.URL+"/", time.UTC
+	forecastNow, _, _, err := fetchWeather(&cfg)
+	if err == nil {
+		err = cityNotFoundError(forecastNow, cfg)
+	}
+	if err == nil || !strings.Contains(err.Error(), "Did you mean: london?") {
+		t.Errorf("fetchWeather() for 404 = %v, want city not found with suggestions", err)
+	}
+}
+
+func Test_fetchWeatherDeadline(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "yandex-weather-cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)                                     // nolint: errcheck
+	defer os.Setenv("XDG_CACHE_HOME", os.Getenv("XDG_CACHE_HOME")) // nolint: errcheck
+	if err := os.Setenv("XDG_CACHE_HOME", tmpDir); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(5 * time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	// main page for time zone is loaded before the others, all of them are limited by one deadline
+	cfg := defaultConfig()
+	cfg.city, cfg.baseURL, cfg.baseURLMini = "moscow", server.URL+"/", server.URL+"/mini/"
+	cfg.cacheTTL, cfg.deadline, cfg.timeZone = time.Minute, 300*time.Millisecond, nil
+	start := time.Now()
+	if _, _, _, err := fetchWeather(&cfg); err == nil {
+		t.Errorf("fetchWeather() after deadline without cache is ok, want error")
+	}
+	if elapsed := time.Since(start); elapsed > 2*cfg.deadline-50*time.Millisecond {
+		t.Errorf("fetchWeather() took %s, want about deadline %s", elapsed, cfg.deadline)
+	}
+}
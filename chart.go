@@ -0,0 +1,226 @@
+// multi-row temperature chart for forecast by hours
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	// chartAxisWidth - width of y-axis with temperature labels: " -12°┤"
+	chartAxisWidth = 6
+	// chartHourWidth - chars per one hour, same as in one-row histogram
+	chartHourWidth = 4
+	// chartIconOffset - icon position inside one hour, aligned with "%3d " hours row
+	chartIconOffset = 2
+	// brailleBlank - empty braille pattern
+	brailleBlank = 0x2800
+)
+
+// brailleDots - bits of braille pattern by [row][column] of dot in one char
+var brailleDots = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+// ----------------------------------------------------------------------------
+// linear interpolation of temperatures, factor values for each hour
+func interpolateTemps(forecastByHours []hourTemp, factor int) []float64 {
+	temperatures := make([]float64, len(forecastByHours)*factor)
+	for i, row := range forecastByHours {
+		currTemp := float64(row.Temp)
+		nextI := i + 1
+		if i == len(forecastByHours)-1 {
+			nextI = i
+		}
+		nextTemp := float64(forecastByHours[nextI].Temp)
+
+		temperatures[i*factor] = currTemp
+
+		for j := 1; j < factor; j++ {
+			temperatures[i*factor+j] = currTemp +
+				(float64(j)/float64(factor))*((nextTemp-currTemp)/1)
+		}
+	}
+
+	return temperatures
+}
+
+// ----------------------------------------------------------------------------
+// min and max of hours temperatures, max always greater than min
+func tempsRange(forecastByHours []hourTemp) (minTemp, maxTemp int) {
+	minTemp, maxTemp = forecastByHours[0].Temp, forecastByHours[0].Temp
+	for _, row := range forecastByHours {
+		if minTemp > row.Temp {
+			minTemp = row.Temp
+		}
+		if maxTemp < row.Temp {
+			maxTemp = row.Temp
+		}
+	}
+
+	if maxTemp == minTemp {
+		maxTemp = minTemp + 1
+	}
+
+	return minTemp, maxTemp
+}
+
+// ----------------------------------------------------------------------------
+// Render chart for forecast by hours with height rows, with y-axis of temperatures
+// and x-axis of hours, night hours are marked in row of hours.
+// Chart rows returned with color tags, see ansiColourString().
+func renderChart(forecastByHours []hourTemp, night []bool, height int, braille bool) []string {
+	if len(forecastByHours) == 0 || height < 1 {
+		return nil
+	}
+
+	var grid [][]string
+	if braille {
+		grid = chartBrailleGrid(forecastByHours, height)
+	} else {
+		grid = chartBlocksGrid(forecastByHours, height)
+	}
+
+	minTemp, maxTemp := tempsRange(forecastByHours)
+	result := make([]string, 0, height+2)
+	prevLabel := ""
+	for r := 0; r < height; r++ {
+		// rows from top (max) to bottom (min)
+		rowTemp := float64(maxTemp)
+		if height > 1 {
+			rowTemp -= float64(r) / float64(height-1) * float64(maxTemp-minTemp)
+		}
+		label := fmt.Sprintf("%4d°", int(math.Round(rowTemp)))
+		if label == prevLabel {
+			label = strings.Repeat(" ", chartAxisWidth-1)
+		} else {
+			prevLabel = label
+		}
+
+		result = append(result, "<hours>"+label+"┤</><hours>"+strings.Join(grid[r], "")+"</>")
+	}
+
+	xAxis := strings.Repeat(" ", chartAxisWidth-1) + "└"
+	for range forecastByHours {
+		xAxis += strings.Repeat("─", chartIconOffset) + "┬" + strings.Repeat("─", chartHourWidth-chartIconOffset-1)
+	}
+	result = append(result, "<hours>"+xAxis+"</>")
+
+	result = append(result, "<hours>"+strings.Repeat(" ", chartAxisWidth)+hoursRow(forecastByHours, night)+"</>")
+
+	return result
+}
+
+// ----------------------------------------------------------------------------
+// chart grid from block chars, each row has 8 gradations
+func chartBlocksGrid(forecastByHours []hourTemp, height int) [][]string {
+	temperatures := interpolateTemps(forecastByHours, chartHourWidth)
+	minTemp, maxTemp := tempsRange(forecastByHours)
+	gradations := len(HistoChars)
+	maxLevel := height * gradations
+
+	grid := newChartGrid(height, len(temperatures))
+	topRows := make([]int, len(temperatures))
+	for col, temp := range temperatures {
+		level := 1 + int(math.Round((temp-float64(minTemp))/float64(maxTemp-minTemp)*float64(maxLevel-1)))
+		for r := 0; r < height; r++ {
+			fill := level - (height-1-r)*gradations
+			switch {
+			case fill >= gradations:
+				grid[r][col] = HistoChars[gradations-1]
+			case fill > 0:
+				grid[r][col] = HistoChars[fill-1]
+			}
+		}
+		topRows[col] = height - 1 - (level-1)/gradations
+	}
+
+	overlayChartIcons(grid, forecastByHours, topRows)
+	return grid
+}
+
+// ----------------------------------------------------------------------------
+// chart grid as braille dots line plot, each char has 2x4 dots
+func chartBrailleGrid(forecastByHours []hourTemp, height int) [][]string {
+	dotsInRow, dotsInCol := len(brailleDots), len(brailleDots[0])
+	temperatures := interpolateTemps(forecastByHours, chartHourWidth*dotsInCol)
+	minTemp, maxTemp := tempsRange(forecastByHours)
+	maxDot := height*dotsInRow - 1
+
+	width := chartHourWidth * len(forecastByHours)
+	cells := make([][]rune, height)
+	for r := range cells {
+		cells[r] = make([]rune, width)
+	}
+
+	topRows := make([]int, width)
+	for i := range topRows {
+		topRows[i] = height - 1
+	}
+
+	prevY := -1
+	for x, temp := range temperatures {
+		// y from top of chart
+		y := maxDot - int(math.Round((temp-float64(minTemp))/float64(maxTemp-minTemp)*float64(maxDot)))
+		fromY, toY := y, y
+		if prevY >= 0 && prevY < y {
+			fromY = prevY + 1
+		} else if prevY > y {
+			toY = prevY - 1
+		}
+		for dotY := fromY; dotY <= toY; dotY++ {
+			cells[dotY/dotsInRow][x/dotsInCol] |= brailleDots[dotY%dotsInRow][x%dotsInCol]
+		}
+		if topRows[x/dotsInCol] > y/dotsInRow {
+			topRows[x/dotsInCol] = y / dotsInRow
+		}
+		prevY = y
+	}
+
+	grid := newChartGrid(height, width)
+	for r, row := range cells {
+		for col, dots := range row {
+			if dots != 0 {
+				grid[r][col] = string(brailleBlank + dots)
+			}
+		}
+	}
+
+	overlayChartIcons(grid, forecastByHours, topRows)
+	return grid
+}
+
+// ----------------------------------------------------------------------------
+// grid of chars filled with spaces
+func newChartGrid(height, width int) [][]string {
+	grid := make([][]string, height)
+	for r := range grid {
+		grid[r] = make([]string, width)
+		for col := range grid[r] {
+			grid[r][col] = " "
+		}
+	}
+	return grid
+}
+
+// ----------------------------------------------------------------------------
+// put precipitation icons above the top of chart for each hour
+func overlayChartIcons(grid [][]string, forecastByHours []hourTemp, topRows []int) {
+	for i, item := range forecastByHours {
+		icon, exists := icons[item.Icon]
+		if !exists {
+			continue
+		}
+
+		col := i*chartHourWidth + chartIconOffset
+		row := topRows[col] - 1
+		if row < 0 {
+			row = 0
+		}
+		grid[row][col] = "</><icon>" + icon + "</><hours>"
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_renderChart(t *testing.T) {
+	forecastByHours := []hourTemp{
+		{Hour: 17, Temp: -3, Icon: "icon_rain"},
+		{Hour: 18, Temp: 1, Icon: ""},
+		{Hour: 19, Temp: 1, Icon: ""},
+		{Hour: 20, Temp: 0, Icon: "icon_snow"},
+	}
+	cfg := config{noColor: true}
+
+	tests := []struct {
+		name    string
+		height  int
+		braille bool
+		want    []string
+	}{
+		{
+			name:   "blocks",
+			height: 3,
+			want: []string{
+				"   1°┤  ☂▂█████▇▅▄▂▂✻▂",
+				"  -1°┤  ▅█████████████",
+				"  -3°┤▁▇██████████████",
+				"     └──┬───┬───┬───┬─",
+				"       17  18  19  20 ",
+			},
+		},
+		{
+			name:    "braille",
+			height:  2,
+			braille: true,
+			want: []string{
+				"   1°┤  ☂⠔⠉⠉⠉⠉⠉⠑⠒⠢⠤⠤✻⠤",
+				"  -3°┤⡠⠊              ",
+				"     └──┬───┬───┬───┬─",
+				"       17  18  19  20 ",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		got := renderChart(forecastByHours, nil, tt.height, tt.braille)
+		if len(got) != len(tt.want) {
+			t.Errorf("%q. renderChart() returned %d lines, want %d", tt.name, len(got), len(tt.want))
+			continue
+		}
+		for i, line := range got {
+			if line = cfg.ansiColourString(line); line != tt.want[i] {
+				t.Errorf("%q. renderChart() line %d = %q, want %q", tt.name, i, line, tt.want[i])
+			}
+		}
+	}
+
+	if got := renderChart(forecastByHours, nil, 0, false); got != nil {
+		t.Errorf("renderChart() with zero height = %v, want nil", got)
+	}
+	if got := renderChart(nil, nil, 5, false); got != nil {
+		t.Errorf("renderChart() without data = %v, want nil", got)
+	}
+}
+
+func Test_renderChartSameTemperature(t *testing.T) {
+	forecastByHours := []hourTemp{{Hour: 1, Temp: -10}, {Hour: 2, Temp: -10}}
+	cfg := config{noColor: true}
+
+	got := renderChart(forecastByHours, nil, 2, false)
+	if bottom := cfg.ansiColourString(got[1]); !strings.HasSuffix(bottom, strings.Repeat(HistoChars[0], 8)) {
+		t.Errorf("renderChart() bottom row = %q, want min level bars", bottom)
+	}
+	if top := cfg.ansiColourString(got[0]); strings.TrimSpace(top) != "-9°┤" {
+		t.Errorf("renderChart() top row = %q, want empty row", top)
+	}
+}
+
+func Test_renderChartNight(t *testing.T) {
+	forecastByHours := []hourTemp{{Hour: 22, Temp: 5}, {Hour: 23, Temp: 4}, {Hour: 7, Temp: 3}, {Hour: 8, Temp: 4}}
+	cfg := config{noColor: true}
+
+	got := renderChart(forecastByHours, []bool{true, true, true, false}, 2, false)
+	if hours := cfg.ansiColourString(got[len(got)-1]); hours != "       22☾ 23☾  7☾  8 " {
+		t.Errorf("renderChart() hours row = %q, want night hours marked without colors", hours)
+	}
+}
@@ -0,0 +1,334 @@
+// offline gazetteer of cities: names, yandex slugs, coordinates and time zones
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// maxCitySuggestions - maximum suggestions for unknown city
+const maxCitySuggestions = 3
+
+// cityInfo - city from gazetteer
+type cityInfo struct {
+	Slug     string   `json:"slug"`      // name of city in yandex URL
+	Name     string   `json:"name"`      // name in English
+	NameRu   string   `json:"name_ru"`   // name in Russian
+	Lat      float64  `json:"lat"`       // latitude
+	Lon      float64  `json:"lon"`       // longitude
+	TimeZone string   `json:"time_zone"` // IANA time zone
+	Aliases  []string `json:"-"`         // other names and spellings
+}
+
+// gazetteer - known cities, order is used for completion and suggestions
+var gazetteer = []cityInfo{
+	{"moscow", "Moscow", "Москва", 55.7558, 37.6173, "Europe/Moscow", []string{"msk"}},
+	{"saint-petersburg", "Saint Petersburg", "Санкт-Петербург", 59.9386, 30.3141, "Europe/Moscow", []string{"st-petersburg", "petersburg", "spb", "leningrad", "Питер"}},
+	{"novosibirsk", "Novosibirsk", "Новосибирск", 55.0084, 82.9357, "Asia/Novosibirsk", nil},
+	{"yekaterinburg", "Yekaterinburg", "Екатеринбург", 56.8389, 60.6057, "Asia/Yekaterinburg", []string{"ekb", "sverdlovsk"}},
+	{"kazan", "Kazan", "Казань", 55.7961, 49.1064, "Europe/Moscow", nil},
+	{"nizhny-novgorod", "Nizhny Novgorod", "Нижний Новгород", 56.3269, 44.0059, "Europe/Moscow", []string{"nizhniy-novgorod", "gorky"}},
+	{"chelyabinsk", "Chelyabinsk", "Челябинск", 55.1644, 61.4368, "Asia/Yekaterinburg", nil},
+	{"samara", "Samara", "Самара", 53.1959, 50.1002, "Europe/Samara", []string{"kuybyshev"}},
+	{"omsk", "Omsk", "Омск", 54.9885, 73.3242, "Asia/Omsk", nil},
+	{"rostov-na-donu", "Rostov-on-Don", "Ростов-на-Дону", 47.2357, 39.7015, "Europe/Moscow", []string{"rostov"}},
+	{"ufa", "Ufa", "Уфа", 54.7388, 55.9721, "Asia/Yekaterinburg", nil},
+	{"krasnoyarsk", "Krasnoyarsk", "Красноярск", 56.0153, 92.8932, "Asia/Krasnoyarsk", nil},
+	{"voronezh", "Voronezh", "Воронеж", 51.6720, 39.1843, "Europe/Moscow", nil},
+	{"perm", "Perm", "Пермь", 58.0105, 56.2502, "Asia/Yekaterinburg", nil},
+	{"volgograd", "Volgograd", "Волгоград", 48.7080, 44.5133, "Europe/Volgograd", []string{"stalingrad"}},
+	{"krasnodar", "Krasnodar", "Краснодар", 45.0355, 38.9753, "Europe/Moscow", nil},
+	{"sochi", "Sochi", "Сочи", 43.5855, 39.7231, "Europe/Moscow", nil},
+	{"kaliningrad", "Kaliningrad", "Калининград", 54.7104, 20.4522, "Europe/Kaliningrad", []string{"konigsberg"}},
+	{"vladivostok", "Vladivostok", "Владивосток", 43.1155, 131.8855, "Asia/Vladivostok", nil},
+	{"khabarovsk", "Khabarovsk", "Хабаровск", 48.4802, 135.0719, "Asia/Vladivostok", nil},
+	{"irkutsk", "Irkutsk", "Иркутск", 52.2870, 104.3050, "Asia/Irkutsk", nil},
+	{"murmansk", "Murmansk", "Мурманск", 68.9585, 33.0827, "Europe/Moscow", nil},
+	{"arkhangelsk", "Arkhangelsk", "Архангельск", 64.5399, 40.5152, "Europe/Moscow", nil},
+	{"yaroslavl", "Yaroslavl", "Ярославль", 57.6261, 39.8845, "Europe/Moscow", nil},
+	{"tula", "Tula", "Тула", 54.1931, 37.6173, "Europe/Moscow", nil},
+	{"tver", "Tver", "Тверь", 56.8587, 35.9176, "Europe/Moscow", []string{"kalinin"}},
+	{"istra", "Istra", "Истра", 55.9150, 36.8600, "Europe/Moscow", nil},
+	{"kyiv", "Kyiv", "Киев", 50.4501, 30.5234, "Europe/Kiev", []string{"kiev", "Київ"}},
+	{"kharkiv", "Kharkiv", "Харьков", 49.9935, 36.2304, "Europe/Kiev", []string{"kharkov", "Харків"}},
+	{"odesa", "Odesa", "Одесса", 46.4825, 30.7233, "Europe/Kiev", []string{"odessa", "Одеса"}},
+	{"lviv", "Lviv", "Львов", 49.8397, 24.0297, "Europe/Kiev", []string{"lvov", "Львів"}},
+	{"dnipro", "Dnipro", "Днепр", 48.4647, 35.0462, "Europe/Kiev", []string{"dnepr", "dnipropetrovsk", "Дніпро"}},
+	{"minsk", "Minsk", "Минск", 53.9006, 27.5590, "Europe/Minsk", nil},
+	{"almaty", "Almaty", "Алматы", 43.2220, 76.8512, "Asia/Almaty", []string{"alma-ata"}},
+	{"astana", "Astana", "Астана", 51.1694, 71.4491, "Asia/Almaty", []string{"nur-sultan"}},
+	{"tashkent", "Tashkent", "Ташкент", 41.2995, 69.2401, "Asia/Tashkent", nil},
+	{"tbilisi", "Tbilisi", "Тбилиси", 41.7151, 44.8271, "Asia/Tbilisi", []string{"tiflis"}},
+	{"yerevan", "Yerevan", "Ереван", 40.1792, 44.4991, "Asia/Yerevan", nil},
+	{"baku", "Baku", "Баку", 40.4093, 49.8671, "Asia/Baku", nil},
+	{"riga", "Riga", "Рига", 56.9496, 24.1052, "Europe/Riga", nil},
+	{"vilnius", "Vilnius", "Вильнюс", 54.6872, 25.2797, "Europe/Vilnius", nil},
+	{"tallinn", "Tallinn", "Таллин", 59.4370, 24.7536, "Europe/Tallinn", nil},
+	{"chisinau", "Chisinau", "Кишинёв", 47.0105, 28.8638, "Europe/Chisinau", []string{"kishinev"}},
+	{"london", "London", "Лондон", 51.5074, -0.1278, "Europe/London", nil},
+	{"paris", "Paris", "Париж", 48.8566, 2.3522, "Europe/Paris", nil},
+	{"berlin", "Berlin", "Берлин", 52.5200, 13.4050, "Europe/Berlin", nil},
+	{"rome", "Rome", "Рим", 41.9028, 12.4964, "Europe/Rome", []string{"roma"}},
+	{"madrid", "Madrid", "Мадрид", 40.4168, -3.7038, "Europe/Madrid", nil},
+	{"prague", "Prague", "Прага", 50.0755, 14.4378, "Europe/Prague", []string{"praha"}},
+	{"warsaw", "Warsaw", "Варшава", 52.2297, 21.0122, "Europe/Warsaw", []string{"warszawa"}},
+	{"vienna", "Vienna", "Вена", 48.2082, 16.3738, "Europe/Vienna", []string{"wien"}},
+	{"istanbul", "Istanbul", "Стамбул", 41.0082, 28.9784, "Europe/Istanbul", nil},
+	{"new-york", "New York", "Нью-Йорк", 40.7128, -74.0060, "America/New_York", []string{"nyc"}},
+	{"tokyo", "Tokyo", "Токио", 35.6762, 139.6503, "Asia/Tokyo", nil},
+	{"beijing", "Beijing", "Пекин", 39.9042, 116.4074, "Asia/Shanghai", []string{"peking"}},
+}
+
+// translitTable - Cyrillic letters of Russian and Ukrainian to Latin
+var translitTable = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh", 'з': "z",
+	'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r",
+	'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+	'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g",
+}
+
+// cityKeys - normalized names of cities from gazetteer for lookup
+var cityKeys = map[string][]string{}
+
+func init() {
+	for _, city := range gazetteer {
+		keys := []string{city.Slug, normalizeCityName(city.Name), normalizeCityName(city.NameRu)}
+		for _, alias := range city.Aliases {
+			keys = append(keys, normalizeCityName(alias))
+		}
+		cityKeys[city.Slug] = keys
+	}
+}
+
+// ----------------------------------------------------------------------------
+// transliterate Cyrillic to Latin, "ий"/"ый" at end of word as "y"
+func transliterate(text string) string {
+	runes := []rune(strings.ToLower(text))
+	result := strings.Builder{}
+	for i := 0; i < len(runes); i++ {
+		latin, ok := translitTable[runes[i]]
+		if !ok {
+			result.WriteRune(runes[i])
+			continue
+		}
+
+		if (runes[i] == 'и' || runes[i] == 'ы') && i+1 < len(runes) && runes[i+1] == 'й' &&
+			(i+2 == len(runes) || !unicode.IsLetter(runes[i+2])) {
+			latin = "y"
+			i++
+		}
+		result.WriteString(latin)
+	}
+
+	return result.String()
+}
+
+// ----------------------------------------------------------------------------
+// normalize city name for lookup: lower case, Latin letters, words joined by "-"
+func normalizeCityName(name string) string {
+	words := strings.FieldsFunc(transliterate(name), func(char rune) bool {
+		return !unicode.IsLetter(char) && !unicode.IsDigit(char)
+	})
+
+	return strings.Join(words, "-")
+}
+
+// ----------------------------------------------------------------------------
+// find city in gazetteer by slug, name in any language or alias
+func lookupCity(name string) (cityInfo, bool) {
+	key := normalizeCityName(name)
+	if key == "" {
+		return cityInfo{}, false
+	}
+
+	for _, city := range gazetteer {
+		if inList(key, cityKeys[city.Slug]) {
+			return city, true
+		}
+	}
+
+	return cityInfo{}, false
+}
+
+// ----------------------------------------------------------------------------
+// yandex slug for city from command line: from gazetteer or transliterated name
+func resolveCity(name string) string {
+	if city, ok := lookupCity(name); ok {
+		return city.Slug
+	}
+
+	for _, char := range name {
+		if _, ok := translitTable[unicode.ToLower(char)]; ok {
+			return normalizeCityName(name)
+		}
+	}
+
+	return name
+}
+
+// ----------------------------------------------------------------------------
+// similar cities for unknown name by edit distance, the closest first
+func suggestCities(name string, limit int) []cityInfo {
+	key := normalizeCityName(name)
+	if key == "" {
+		return nil
+	}
+	maxDistance := len([]rune(key)) / 3
+	if maxDistance < 1 {
+		maxDistance = 1
+	}
+
+	type suggestion struct {
+		city     cityInfo
+		distance int
+	}
+	suggestions := []suggestion{}
+	for _, city := range gazetteer {
+		distance := -1
+		for _, cityKey := range cityKeys[city.Slug] {
+			if d := levenshtein(key, cityKey); distance < 0 || d < distance {
+				distance = d
+			}
+		}
+		if distance > 0 && distance <= maxDistance {
+			suggestions = append(suggestions, suggestion{city: city, distance: distance})
+		}
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool { return suggestions[i].distance < suggestions[j].distance })
+
+	result := []cityInfo{}
+	for i := 0; i < len(suggestions) && i < limit; i++ {
+		result = append(result, suggestions[i].city)
+	}
+
+	return result
+}
+
+// ----------------------------------------------------------------------------
+// search cities by part of name, similar cities if nothing found
+func searchCities(query string) []cityInfo {
+	key := normalizeCityName(query)
+	if key == "" {
+		return nil
+	}
+
+	result := []cityInfo{}
+	for _, city := range gazetteer {
+		for _, cityKey := range cityKeys[city.Slug] {
+			if strings.Contains(cityKey, key) {
+				result = append(result, city)
+				break
+			}
+		}
+	}
+
+	if len(result) == 0 {
+		return suggestCities(query, maxCitySuggestions)
+	}
+
+	return result
+}
+
+// ----------------------------------------------------------------------------
+// nearest city from gazetteer and distance to it in km
+func nearestCity(point geoPoint) (cityInfo, float64) {
+	result, minDistance := cityInfo{}, math.Inf(1)
+	for _, city := range gazetteer {
+		if distance := distanceKm(point, geoPoint{Lat: city.Lat, Lon: city.Lon}); distance < minDistance {
+			result, minDistance = city, distance
+		}
+	}
+
+	return result, minDistance
+}
+
+// ----------------------------------------------------------------------------
+// great-circle distance between points in km
+func distanceKm(a, b geoPoint) float64 {
+	const earthRadius = 6371.0
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat, dLon := toRad(b.Lat-a.Lat), toRad(b.Lon-a.Lon)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(toRad(a.Lat))*math.Cos(toRad(b.Lat))*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadius * math.Asin(math.Sqrt(h))
+}
+
+// ----------------------------------------------------------------------------
+// edit distance between strings
+func levenshtein(a, b string) int {
+	runesA, runesB := []rune(a), []rune(b)
+	prev := make([]int, len(runesB)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(runesA); i++ {
+		cur := make([]int, len(runesB)+1)
+		cur[0] = i
+		for j := 1; j <= len(runesB); j++ {
+			cost := 1
+			if runesA[i-1] == runesB[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(minInt(prev[j]+1, cur[j-1]+1), prev[j-1]+cost)
+		}
+		prev = cur
+	}
+
+	return prev[len(runesB)]
+}
+
+// ----------------------------------------------------------------------------
+// minimum of two integers
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// ----------------------------------------------------------------------------
+// slugs of cities for message
+func citySlugs(cities []cityInfo) []string {
+	result := []string{}
+	for _, city := range cities {
+		result = append(result, city.Slug)
+	}
+	return result
+}
+
+// ----------------------------------------------------------------------------
+// print cities found in gazetteer
+func runCitiesSearch(cfg config, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "query is required")
+		return 1
+	}
+
+	cities := searchCities(strings.Join(args, " "))
+	if len(cities) == 0 {
+		fmt.Fprintf(os.Stderr, "no cities found for %q\n", strings.Join(args, " "))
+		return 1
+	}
+
+	if cfg.getJSON {
+		jsonBytes, _ := json.Marshal(cities)
+		fmt.Println(string(jsonBytes))
+		return 0
+	}
+
+	outWriter := getColorWriter(cfg.terminal)
+	for _, city := range cities {
+		outWriter.Print(cfg.ansiColourString(fmt.Sprintf("<value>%-18s</> %-18s %-18s %9.4f %9.4f  %s\n",
+			city.Slug, city.Name, city.NameRu, city.Lat, city.Lon, city.TimeZone)))
+	}
+
+	return 0
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_gazetteer(t *testing.T) {
+	slugs := map[string]bool{}
+	for _, city := range gazetteer {
+		if slugs[city.Slug] {
+			t.Errorf("duplicate city %q", city.Slug)
+		}
+		slugs[city.Slug] = true
+
+		if city.Slug != normalizeCityName(city.Slug) {
+			t.Errorf("slug %q is not normalized", city.Slug)
+		}
+		if city.Lat < -90 || city.Lat > 90 || city.Lon < -180 || city.Lon > 180 {
+			t.Errorf("invalid coordinates of %q: %f, %f", city.Slug, city.Lat, city.Lon)
+		}
+		if _, err := time.LoadLocation(city.TimeZone); err != nil {
+			t.Errorf("invalid time zone of %q: %s", city.Slug, err)
+		}
+	}
+}
+
+func Test_transliterate(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Москва", "moskva"},
+		{"Нижний Новгород", "nizhny novgorod"},
+		{"Красный Яр", "krasny yar"},
+		{"Щёлково", "shchelkovo"},
+		{"Дніпро", "dnipro"},
+		{"Подъячий", "podyachy"},
+		{"London", "london"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := transliterate(tt.in); got != tt.want {
+			t.Errorf("transliterate(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func Test_lookupCity(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Киев", "kyiv"},
+		{"kiev", "kyiv"},
+		{"Kyiv", "kyiv"},
+		{"КИЇВ", "kyiv"},
+		{"Санкт-Петербург", "saint-petersburg"},
+		{"Saint Petersburg", "saint-petersburg"},
+		{"spb", "saint-petersburg"},
+		{"nizhny novgorod", "nizhny-novgorod"},
+		{"Нижний Новгород", "nizhny-novgorod"},
+		{"Rostov-on-Don", "rostov-na-donu"},
+		{"Кишинёв", "chisinau"},
+		{"new_york", "new-york"},
+		{"unknown", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		city, ok := lookupCity(tt.name)
+		if city.Slug != tt.want || ok != (tt.want != "") {
+			t.Errorf("lookupCity(%q) = %q, %v, want %q", tt.name, city.Slug, ok, tt.want)
+		}
+	}
+}
+
+func Test_resolveCity(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Киев", "kyiv"},
+		{"London", "london"},
+		{"Тамбов", "tambov"},
+		{"Великий Новгород", "veliky-novgorod"},
+		{"some-village", "some-village"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := resolveCity(tt.name); got != tt.want {
+			t.Errorf("resolveCity(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func Test_levenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"london", "londn", 1},
+		{"киев", "кеив", 2},
+	}
+
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func Test_suggestCities(t *testing.T) {
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"londn", []string{"london"}},
+		{"moskow", []string{"moscow"}},
+		{"Масква", []string{"moscow"}},
+		{"kiyv", []string{"kyiv"}},
+		{"london", []string{}},
+		{"xyzxyzxyz", []string{}},
+		{"", []string{}},
+	}
+
+	for _, tt := range tests {
+		if got := citySlugs(suggestCities(tt.name, maxCitySuggestions)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("suggestCities(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func Test_searchCities(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"петер", []string{"saint-petersburg"}},
+		{"novgorod", []string{"nizhny-novgorod"}},
+		{"kiev", []string{"kyiv"}},
+		{"krasno", []string{"krasnoyarsk", "krasnodar"}},
+		{"berln", []string{"berlin"}},
+		{"zzzz", []string{}},
+	}
+
+	for _, tt := range tests {
+		if got := citySlugs(searchCities(tt.query)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("searchCities(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
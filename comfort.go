@@ -0,0 +1,139 @@
+// comfort metrics from current weather: dew point, heat index, wind chill and humidex
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// thresholds of comfort formulas
+const (
+	// heatIndexMinTemp - heat index is defined from 80°F
+	heatIndexMinTemp = 26.7
+	// windChillMaxTemp - wind chill is defined up to 10°C
+	windChillMaxTemp = 10.0
+	// windChillMinWind - wind chill is defined from 4.8 km/h
+	windChillMinWind = 4.8
+)
+
+// comfortMetrics - computed comfort indices, temperatures in units of output
+type comfortMetrics struct {
+	DewPoint  int  `json:"dew_point"`
+	Humidex   int  `json:"humidex"`
+	HeatIndex *int `json:"heat_index,omitempty"`
+	WindChill *int `json:"wind_chill,omitempty"`
+}
+
+// ----------------------------------------------------------------------------
+// comfort metrics from temperature in Celsius, humidity and wind of current weather,
+// false if humidity is unknown
+func computeComfort(forecastNow map[string]interface{}) (comfortMetrics, bool) {
+	temp, ok := forecastNow["term_now"].(int)
+	if !ok {
+		return comfortMetrics{}, false
+	}
+	humidityText, _ := forecastNow["humidity"].(string)
+	humidity, ok := parseNumber(humidityText)
+	if !ok || humidity <= 0 || humidity > 100 {
+		return comfortMetrics{}, false
+	}
+
+	dewPointTemp := dewPoint(float64(temp), humidity)
+	result := comfortMetrics{
+		DewPoint: int(math.Round(dewPointTemp)),
+		Humidex:  int(math.Round(humidex(float64(temp), dewPointTemp))),
+	}
+
+	if value, ok := heatIndex(float64(temp), humidity); ok {
+		rounded := int(math.Round(value))
+		result.HeatIndex = &rounded
+	}
+
+	windText, _ := forecastNow["wind"].(string)
+	if wind, ok := parseNumber(windText); ok {
+		if value, ok := windChill(float64(temp), wind*3.6); ok {
+			rounded := int(math.Round(value))
+			result.WindChill = &rounded
+		}
+	}
+
+	return result, true
+}
+
+// ----------------------------------------------------------------------------
+// dew point in Celsius by Magnus formula, humidity in %
+func dewPoint(temp, humidity float64) float64 {
+	const a, b = 17.62, 243.12
+	gamma := math.Log(humidity/100) + a*temp/(b+temp)
+	return b * gamma / (a - gamma)
+}
+
+// ----------------------------------------------------------------------------
+// heat index in Celsius by NOAA algorithm (Rothfusz regression with adjustments),
+// false for temperatures below 26.7°C
+func heatIndex(temp, humidity float64) (float64, bool) {
+	if temp < heatIndexMinTemp {
+		return 0, false
+	}
+
+	t := temp*9/5 + 32
+	result := 0.5 * (t + 61 + (t-68)*1.2 + humidity*0.094)
+	if (result+t)/2 >= 80 {
+		result = -42.379 + 2.04901523*t + 10.14333127*humidity -
+			0.22475541*t*humidity - 0.00683783*t*t - 0.05481717*humidity*humidity +
+			0.00122874*t*t*humidity + 0.00085282*t*humidity*humidity - 0.00000199*t*t*humidity*humidity
+
+		switch {
+		case humidity < 13 && t >= 80 && t <= 112:
+			result -= (13 - humidity) / 4 * math.Sqrt((17-math.Abs(t-95))/17)
+		case humidity > 85 && t >= 80 && t <= 87:
+			result += (humidity - 85) / 10 * (87 - t) / 5
+		}
+	}
+
+	return (result - 32) * 5 / 9, true
+}
+
+// ----------------------------------------------------------------------------
+// wind chill in Celsius by formula of Environment Canada and NWS, wind in km/h,
+// false for temperatures above 10°C or light wind
+func windChill(temp, wind float64) (float64, bool) {
+	if temp > windChillMaxTemp || wind < windChillMinWind {
+		return 0, false
+	}
+
+	v := math.Pow(wind, 0.16)
+	return 13.12 + 0.6215*temp - 11.37*v + 0.3965*temp*v, true
+}
+
+// ----------------------------------------------------------------------------
+// humidex by formula of Environment Canada, from temperature and dew point in Celsius
+func humidex(temp, dewPointTemp float64) float64 {
+	vapourPressure := 6.11 * math.Exp(5417.7530*(1/273.16-1/(273.15+dewPointTemp)))
+	return temp + 0.5555*(vapourPressure-10)
+}
+
+// ----------------------------------------------------------------------------
+// "Комфорт" section of current weather
+func renderComfort(comfort comfortMetrics, cfg config) []string {
+	rows := []struct {
+		label string
+		value *int
+	}{
+		{"dew_point", &comfort.DewPoint},
+		{"humidex", &comfort.Humidex},
+		{"heat_index", comfort.HeatIndex},
+		{"wind_chill", comfort.WindChill},
+	}
+
+	result := []string{cfg.tr("comfort") + ":"}
+	for _, row := range rows {
+		if row.value == nil {
+			continue
+		}
+		result = append(result, fmt.Sprintf("  %s: <%s>%d %s</>",
+			cfg.tr(row.label), cfg.tempColor(float64(*row.value)), *row.value, cfg.tempUnit()))
+	}
+
+	return result
+}
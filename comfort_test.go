@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func Test_dewPoint(t *testing.T) {
+	tests := []struct {
+		temp, humidity, want float64
+	}{
+		{20, 50, 9.3},
+		{30, 70, 23.9},
+		{0, 80, -3.0},
+		{-10, 90, -11.4},
+		{25, 100, 25},
+	}
+
+	for _, tt := range tests {
+		if got := dewPoint(tt.temp, tt.humidity); math.Abs(got-tt.want) > 0.1 {
+			t.Errorf("dewPoint(%v, %v) = %.2f, want %.1f", tt.temp, tt.humidity, got, tt.want)
+		}
+	}
+}
+
+func Test_heatIndex(t *testing.T) {
+	// reference values from NWS heat index chart, °F converted to °C
+	tests := []struct {
+		temp, humidity float64
+		want           float64
+		wantOk         bool
+	}{
+		{32.2, 70, 41.1, true}, // 90°F, 70% -> 106°F
+		{35, 50, 40.6, true},   // 95°F, 50% -> 105°F
+		{26.7, 40, 26.7, true}, // 80°F, 40% -> 80°F
+		{30, 90, 40.6, true},   // 86°F, 90% -> 105°F
+		{37.8, 40, 42.8, true}, // 100°F, 40% -> 109°F
+		{20, 50, 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := heatIndex(tt.temp, tt.humidity)
+		if ok != tt.wantOk || math.Abs(got-tt.want) > 0.7 {
+			t.Errorf("heatIndex(%v, %v) = %.2f, %v, want %.1f", tt.temp, tt.humidity, got, ok, tt.want)
+		}
+	}
+}
+
+func Test_windChill(t *testing.T) {
+	// reference values from Environment Canada wind chill chart
+	tests := []struct {
+		temp, wind float64
+		want       float64
+		wantOk     bool
+	}{
+		{-10, 20, -18, true},
+		{0, 10, -3, true},
+		{-20, 30, -33, true},
+		{5, 40, -1, true},
+		{15, 20, 0, false},
+		{-10, 3, 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := windChill(tt.temp, tt.wind)
+		if ok != tt.wantOk || math.Abs(got-tt.want) > 0.5 {
+			t.Errorf("windChill(%v, %v) = %.2f, %v, want %.0f", tt.temp, tt.wind, got, ok, tt.want)
+		}
+	}
+}
+
+func Test_humidex(t *testing.T) {
+	// reference values from Environment Canada humidex table
+	tests := []struct {
+		temp, dewPoint, want float64
+	}{
+		{30, 15, 34},
+		{35, 25, 47},
+		{25, 20, 33},
+		{20, 5, 19},
+	}
+
+	for _, tt := range tests {
+		if got := humidex(tt.temp, tt.dewPoint); math.Abs(got-tt.want) > 0.6 {
+			t.Errorf("humidex(%v, %v) = %.2f, want %.0f", tt.temp, tt.dewPoint, got, tt.want)
+		}
+	}
+}
+
+func Test_computeComfort(t *testing.T) {
+	tests := []struct {
+		name        string
+		forecastNow map[string]interface{}
+		want        string
+		wantOk      bool
+	}{
+		{
+			name:        "cold and windy",
+			forecastNow: map[string]interface{}{"term_now": -10, "humidity": "80%", "wind": "5,5 м/с, СЗ"},
+			want:        "dew -13, humidex -14, heat -, chill -18",
+			wantOk:      true,
+		},
+		{
+			name:        "hot and humid",
+			forecastNow: map[string]interface{}{"term_now": 32, "humidity": "70%", "wind": "0 м/с"},
+			want:        "dew 26, humidex 45, heat 40, chill -",
+			wantOk:      true,
+		},
+		{
+			name:        "without humidity",
+			forecastNow: map[string]interface{}{"term_now": 20, "humidity": "", "wind": "3 м/с"},
+		},
+		{
+			name:        "without temperature",
+			forecastNow: map[string]interface{}{"humidity": "50%"},
+		},
+	}
+
+	optional := func(value *int) string {
+		if value == nil {
+			return "-"
+		}
+		return fmt.Sprint(*value)
+	}
+
+	for _, tt := range tests {
+		comfort, ok := computeComfort(tt.forecastNow)
+		if ok != tt.wantOk {
+			t.Errorf("%q. computeComfort() ok = %v", tt.name, ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		got := fmt.Sprintf("dew %d, humidex %d, heat %s, chill %s", comfort.DewPoint, comfort.Humidex, optional(comfort.HeatIndex), optional(comfort.WindChill))
+		if got != tt.want {
+			t.Errorf("%q. computeComfort() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,592 @@
+// tree of subcommands, usage and man page are generated from it
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// command - subcommand of command line, root command runs without name
+type command struct {
+	name     string
+	args     string                              // arguments for usage: "[city]"
+	short    string                              // one line description
+	long     string                              // description for help and man page
+	examples []string                            // examples of arguments
+	flags    func(fs *flag.FlagSet, cfg *config) // own flags of command, common flags added to all commands
+	run      func(cfg config, args []string) int // nil for group of commands
+	hidden   bool                                // don't show in usage
+	rawArgs  bool                                // don't parse flags, pass all args to run
+	parent   *command
+	commands []*command
+}
+
+// envVars - environment variables for usage and man page
+var envVars = []struct {
+	name, desc string
+}{
+	{envBaseURLName, "yandex pogoda URL"},
+	{envBaseURLMiniName, "yandex URL for forecast by hours"},
+	{envThemeName, "color theme"},
+	{envProfileName, "profile from config file"},
+	{envConfigName, "path of config file"},
+	{"NO_COLOR", "disable colors in auto color mode"},
+	{"CLICOLOR_FORCE", "enable colors in auto color mode when output is piped"},
+	{"COLORTERM", "truecolor or 24bit for 24-bit colors"},
+}
+
+// ----------------------------------------------------------------------------
+// tree of all commands
+func commandTree() *command {
+	root := &command{
+		args:  "[city]",
+		short: "Command line interface for Yandex weather service",
+		long: "Show current weather, forecast by hours and forecast by days. " +
+			"By default the city is detected by Yandex from your location.",
+		examples: []string{"", "kyiv", "-json london", "-chart-height 6 london", "-profile dacha", "-lat 55.915 -lon 36.86", "geo:55.915,36.86"},
+		flags: func(fs *flag.FlagSet, cfg *config) {
+			addNowFlags(fs, cfg)
+			addHoursFlags(fs, cfg)
+			addDaysFlags(fs, cfg)
+			addBestDayFlags(fs, cfg)
+			fs.BoolVar(&cfg.noToday, "no-today", cfg.noToday, "disable today forecast")
+			fs.BoolVar(&cfg.getVersion, "version", cfg.getVersion, "get version")
+		},
+		run: runForecast,
+	}
+
+	root.add(&command{
+		name:     "now",
+		args:     "[city]",
+		short:    "current weather",
+		long:     "Show current weather: temperature, conditions, pressure, humidity and wind.",
+		examples: []string{"now kyiv", "now -comfort kyiv"},
+		flags:    addNowFlags,
+		run: func(cfg config, args []string) int {
+			cfg.view, cfg.noToday, cfg.daysLimit = viewNow, true, 0
+			return runForecast(cfg, args)
+		},
+	})
+	root.add(&command{
+		name:     "hours",
+		args:     "[city]",
+		short:    "forecast by hours",
+		long:     "Show forecast by hours for the next hours as histogram or chart.",
+		examples: []string{"hours -chart-height 8 -braille london", "hours -hours-days 3 istra"},
+		flags:    addHoursFlags,
+		run: func(cfg config, args []string) int {
+			cfg.view, cfg.noToday, cfg.daysLimit = viewHours, false, 0
+			return runForecast(cfg, args)
+		},
+	})
+	root.add(&command{
+		name:     "days",
+		args:     "[city]",
+		short:    "forecast by days",
+		long:     "Show forecast by days with day and night temperatures.",
+		examples: []string{"days -days 5 -days-chart kyiv", "days -include-today -weekends moscow", "days -from 2021-10-25 -to 2021-10-31 london", "days -detailed -days 3 istra"},
+		flags:    addDaysFlags,
+		run: func(cfg config, args []string) int {
+			cfg.view, cfg.noToday = viewDays, true
+			return runForecast(cfg, args)
+		},
+	})
+
+	root.add(&command{
+		name:  "rain",
+		args:  "[city]",
+		short: "when will it rain",
+		long: fmt.Sprintf("Answer in one line when the rain starts or stops, by forecast by hours and by days. "+
+			"Exit code is 0 if it rains now or in the next hours, %d if not.", exitCodeNoRain),
+		examples: []string{"rain kyiv", "rain -json istra"},
+		run:      runRain,
+	})
+
+	root.add(&command{
+		name:  "best-day",
+		args:  "[city...]",
+		short: "rank days of forecast for outdoor events",
+		long: "Rank days of forecast by scoring profile: day temperature in range, no precipitation, " +
+			"wind limit and weekend preference, with points of each criterion. " +
+			"Several cities are ranked in one list, profile may be saved in config file.",
+		examples: []string{"best-day -temp-min 18 -temp-max 26 -prefer-weekends istra", "best-day -wind-max 5 moscow istra tver"},
+		flags: func(fs *flag.FlagSet, cfg *config) {
+			addDaysWindowFlags(fs, cfg)
+			addBestDayFlags(fs, cfg)
+		},
+		run: runBestDay,
+	})
+
+	configCmd := root.add(&command{
+		name:  "config",
+		short: "configuration",
+		long:  "Commands for config file.",
+	})
+	configCmd.add(&command{
+		name:     "show",
+		short:    "print effective configuration",
+		long:     "Print configuration merged from flags, environment, profile and config file, with source of each value.",
+		examples: []string{"-profile dacha config show"},
+		run: func(cfg config, _ []string) int {
+			fmt.Print(formatConfig(cfg))
+			return 0
+		},
+	})
+
+	citiesCmd := root.add(&command{
+		name:  "cities",
+		short: "offline gazetteer of cities",
+		long: "Commands for built-in list of cities. City argument of forecast commands is looked up in it " +
+			"by name in English, Russian or Ukrainian, Cyrillic names are transliterated.",
+	})
+	citiesCmd.add(&command{
+		name:     "search",
+		args:     "<query>",
+		short:    "search cities by name",
+		long:     "Print cities with name containing query: slug for yandex URL, names, coordinates and time zone. Similar cities are printed if nothing found.",
+		examples: []string{"cities search петер", "-json cities search kiev"},
+		run:      runCitiesSearch,
+	})
+
+	root.add(&command{
+		name:  "help",
+		args:  "[command]",
+		short: "help for command",
+		long:  "Print usage of command.",
+		run: func(_ config, args []string) int {
+			cmd, _ := root.find(args)
+			cmd.usage()
+			return 0
+		},
+	})
+	root.add(&command{
+		name:     "completion",
+		args:     "<shell>",
+		short:    "print shell completion script",
+		long:     "Print completion script for bash, zsh or fish, it completes commands, options and cities.",
+		examples: []string{"completion bash > /etc/bash_completion.d/" + appName},
+		run:      runCompletion,
+	})
+	root.add(&command{
+		name:    completeCommandName,
+		args:    "[words]",
+		short:   "print candidates for completion of last word",
+		long:    "Entry point for completion scripts.",
+		hidden:  true,
+		rawArgs: true,
+		run: func(cfg config, args []string) int {
+			return runComplete(root, cfg, args)
+		},
+	})
+	root.add(&command{
+		name:  "version",
+		short: "print version",
+		long:  "Print version of program.",
+		run: func(config, []string) int {
+			fmt.Println(version)
+			return 0
+		},
+	})
+	root.add(&command{
+		name:  "man",
+		short: "print man page",
+		long:  "Print man page in roff format, generated from commands.",
+		run: func(config, []string) int {
+			fmt.Print(root.manPage())
+			return 0
+		},
+	})
+
+	return root
+}
+
+// ----------------------------------------------------------------------------
+// add subcommand
+func (cmd *command) add(subCmd *command) *command {
+	subCmd.parent = cmd
+	cmd.commands = append(cmd.commands, subCmd)
+	return subCmd
+}
+
+// ----------------------------------------------------------------------------
+// find subcommand by names from args, returns command and rest of args
+func (cmd *command) find(args []string) (*command, []string) {
+	for len(args) > 0 {
+		subCmd := cmd.subCommand(args[0])
+		if subCmd == nil {
+			break
+		}
+		cmd, args = subCmd, args[1:]
+	}
+
+	return cmd, args
+}
+
+// ----------------------------------------------------------------------------
+// subcommand by name
+func (cmd *command) subCommand(name string) *command {
+	for _, subCmd := range cmd.commands {
+		if subCmd.name == name {
+			return subCmd
+		}
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// full name of command: "config show"
+func (cmd *command) fullName() string {
+	names := []string{}
+	for c := cmd; c != nil && c.name != ""; c = c.parent {
+		names = append([]string{c.name}, names...)
+	}
+
+	return strings.Join(names, " ")
+}
+
+// ----------------------------------------------------------------------------
+// synopsis of command: "yandex-weather-cli hours [options] [city]"
+func (cmd *command) synopsis() string {
+	parts := []string{appName}
+	if name := cmd.fullName(); name != "" {
+		parts = append(parts, name)
+	}
+	if cmd.run != nil {
+		parts = append(parts, "[options]")
+	} else {
+		parts = append(parts, "<command>")
+	}
+	if cmd.args != "" {
+		parts = append(parts, cmd.args)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// ----------------------------------------------------------------------------
+// flag set with common and own flags of command
+func (cmd *command) flagSet(cfg *config) *flag.FlagSet {
+	fs := flag.NewFlagSet(cmd.synopsis(), flag.ExitOnError)
+	if cmd.run != nil && !cmd.rawArgs {
+		addCommonFlags(fs, cfg)
+	}
+	if cmd.flags != nil {
+		cmd.flags(fs, cfg)
+	}
+	fs.Usage = cmd.usage
+
+	return fs
+}
+
+// ----------------------------------------------------------------------------
+// visible subcommands
+func (cmd *command) visibleCommands() []*command {
+	result := []*command{}
+	for _, subCmd := range cmd.commands {
+		if !subCmd.hidden {
+			result = append(result, subCmd)
+		}
+	}
+	return result
+}
+
+// ----------------------------------------------------------------------------
+// print usage of command
+func (cmd *command) usage() {
+	fmt.Printf("Usage: %s\n", cmd.synopsis())
+	if cmd.parent == nil {
+		fmt.Printf("       %s <command> [options] [args]\n", appName)
+	}
+	fmt.Printf("\n%s\n", cmd.long)
+
+	if commands := cmd.visibleCommands(); len(commands) > 0 {
+		fmt.Println("\ncommands:")
+		for _, subCmd := range commands {
+			fmt.Printf("  %-10s %s\n", subCmd.name, subCmd.short)
+		}
+	}
+
+	if cmd.run != nil {
+		fmt.Println("\noptions:")
+		cfg := defaultConfig()
+		fs := cmd.flagSet(&cfg)
+		fs.SetOutput(os.Stdout)
+		fs.PrintDefaults()
+	}
+
+	if len(cmd.examples) > 0 {
+		fmt.Println("\nexamples:")
+		for _, example := range cmd.examples {
+			fmt.Printf("  %s\n", strings.TrimSpace(appName+" "+example))
+		}
+	}
+}
+
+// ----------------------------------------------------------------------------
+// common flags for all commands
+func addCommonFlags(fs *flag.FlagSet, cfg *config) {
+	fs.BoolVar(&cfg.getJSON, "json", cfg.getJSON, "get JSON, same as -format json")
+	fs.StringVar(&cfg.format, "format", cfg.format, "output format: text, json, summary, accessible, oneline, waybar, i3bar or polybar")
+	fs.BoolVar(&cfg.summary, "summary", cfg.summary, "get forecast as few sentences, same as -format summary")
+	fs.BoolVar(&cfg.accessible, "accessible", cfg.accessible, "screen reader friendly output as labelled statements, same as -format accessible")
+	fs.BoolVar(&cfg.oneline, "oneline", cfg.oneline, "current weather in one line by -template for shell prompts, same as -format oneline")
+	fs.StringVar(&cfg.template, "template", cfg.template, "template of -oneline output with {icon}, {temp}, {units}, {desc}, {city}, {wind}, {humidity}, {pressure} and {warning}")
+	fs.BoolVar(&cfg.noColor, "no-color", cfg.noColor, "disable colored output, same as -color never")
+	fs.StringVar(&cfg.colorMode, "color", cfg.colorMode, "colored output: auto, always or never")
+	fs.StringVar(&cfg.theme, "theme", cfg.theme, "color theme: "+themesNames())
+	fs.StringVar(&cfg.units, "units", cfg.units, "temperature units: c or f")
+	fs.StringVar(&cfg.lang, "lang", cfg.lang, "language of output: ru or en")
+	fs.StringVar(&cfg.lat, "lat", cfg.lat, "latitude of location, with -lon instead of city")
+	fs.StringVar(&cfg.lon, "lon", cfg.lon, "longitude of location, with -lat instead of city")
+	fs.StringVar(&cfg.asOf, "as-of", cfg.asOf, "render as if it were this moment: 2006-01-02T15:04, uses only saved or cached pages of any age")
+	fs.StringVar(&cfg.page, "page", cfg.page, "render saved yandex page from file")
+	fs.StringVar(&cfg.pageMini, "page-mini", cfg.pageMini, "render saved yandex page for forecast by hours from file")
+	fs.StringVar(&cfg.pageDetails, "page-details", cfg.pageDetails, "render saved yandex details page for -detailed from file")
+	fs.StringVar(&cfg.alert, "alert", cfg.alert, fmt.Sprintf("exit with code %d if there are weather warnings of severity or higher: %s", exitCodeAlert, strings.Join(warningSeverities, ", ")))
+	fs.DurationVar(&cfg.cacheTTL, "cache-ttl", cfg.cacheTTL, "cache yandex pages for duration, for example 10m (0 - without cache), 10m by default for -oneline and bars, not more than half of -interval")
+	fs.DurationVar(&cfg.deadline, "deadline", cfg.deadline, "network deadline for all pages, cached page of any age is used after it, for example 2s (0 - without deadline), 2s by default for -oneline and bars")
+	fs.DurationVar(&cfg.interval, "interval", cfg.interval, "refresh interval of waybar, i3bar or polybar output, for example 10m (0 - once), 10m by default for i3bar")
+	fs.StringVar(&cfg.profile, "profile", cfg.profile, "profile from config file")
+	fs.StringVar(&cfg.configPath, "config", cfg.configPath, "path of config file")
+}
+
+// ----------------------------------------------------------------------------
+// flags for current weather
+func addNowFlags(fs *flag.FlagSet, cfg *config) {
+	fs.BoolVar(&cfg.comfort, "comfort", cfg.comfort, "show comfort metrics: dew point, humidex, heat index and wind chill")
+}
+
+// ----------------------------------------------------------------------------
+// flags for forecast by hours
+func addHoursFlags(fs *flag.FlagSet, cfg *config) {
+	fs.IntVar(&cfg.chartHeight, "chart-height", cfg.chartHeight, "height of forecast by hours chart in rows (0 - one row histogram)")
+	fs.BoolVar(&cfg.braille, "braille", cfg.braille, "draw forecast by hours chart as braille dots line")
+	fs.IntVar(&cfg.hoursDays, "hours-days", cfg.hoursDays, "forecast by hours for days from details page, one strip per day (0 - next hours)")
+}
+
+// ----------------------------------------------------------------------------
+// flags for forecast by days
+func addDaysFlags(fs *flag.FlagSet, cfg *config) {
+	addDaysWindowFlags(fs, cfg)
+	fs.BoolVar(&cfg.daysChart, "days-chart", cfg.daysChart, "show chart of day/night temperatures below forecast by days")
+	fs.BoolVar(&cfg.detailed, "detailed", cfg.detailed, "show morning, day, evening and night in forecast by days")
+}
+
+// ----------------------------------------------------------------------------
+// flags for days of forecast by days
+func addDaysWindowFlags(fs *flag.FlagSet, cfg *config) {
+	fs.IntVar(&cfg.daysLimit, "days", cfg.daysLimit, "maximum days to show")
+	fs.BoolVar(&cfg.includeToday, "include-today", cfg.includeToday, "include today in forecast by days")
+	fs.IntVar(&cfg.daysOffset, "offset", cfg.daysOffset, "skip days from start of forecast by days, can't be used with -from")
+	fs.StringVar(&cfg.dateFrom, "from", cfg.dateFrom, "first date of forecast by days: 2006-01-02")
+	fs.StringVar(&cfg.dateTo, "to", cfg.dateTo, "last date of forecast by days: 2006-01-02")
+	fs.BoolVar(&cfg.weekendsOnly, "weekends", cfg.weekendsOnly, "only Saturday and Sunday in forecast by days")
+}
+
+// ----------------------------------------------------------------------------
+// flags of scoring profile for "best-day" command
+func addBestDayFlags(fs *flag.FlagSet, cfg *config) {
+	fs.IntVar(&cfg.tempMin, "temp-min", cfg.tempMin, "best-day: minimal comfortable day temperature, °C")
+	fs.IntVar(&cfg.tempMax, "temp-max", cfg.tempMax, "best-day: maximal comfortable day temperature, °C")
+	fs.IntVar(&cfg.windMax, "wind-max", cfg.windMax, "best-day: maximal comfortable wind speed, m/s")
+	fs.BoolVar(&cfg.dry, "dry", cfg.dry, "best-day: prefer days without precipitation")
+	fs.BoolVar(&cfg.preferWeekends, "prefer-weekends", cfg.preferWeekends, "best-day: prefer Saturday and Sunday")
+}
+
+// ----------------------------------------------------------------------------
+// flag set with all configurable flags, for merge settings from all sources
+func newFlagSet(cfg *config) *flag.FlagSet {
+	return commandTree().flagSet(cfg)
+}
+
+// ----------------------------------------------------------------------------
+// parse args: flags of root command, name of subcommand and its flags,
+// returns command, values of flags from command line and positional args
+func parseArgs(root *command, args []string) (*command, map[string]string, []string) {
+	flagsSettings := map[string]string{}
+	cmd := root
+	for !cmd.rawArgs {
+		cfg := defaultConfig()
+		fs := cmd.flagSet(&cfg)
+		_ = fs.Parse(args) // exit on error
+		fs.Visit(func(f *flag.Flag) {
+			flagsSettings[configKey(f.Name)] = f.Value.String()
+		})
+		if cfg.getVersion {
+			fmt.Println(version)
+			os.Exit(0)
+		}
+
+		args = fs.Args()
+		subCmd := (*command)(nil)
+		if len(args) > 0 {
+			subCmd = cmd.subCommand(args[0])
+		}
+		if subCmd == nil {
+			break
+		}
+		cmd, args = subCmd, args[1:]
+	}
+
+	return cmd, flagsSettings, args
+}
+
+// ----------------------------------------------------------------------------
+// run command by args of command line, returns exit code
+func runCommand(args []string) int {
+	root := commandTree()
+	cmd, flagsSettings, args := parseArgs(root, args)
+	if cmd.run == nil {
+		cmd.usage()
+		return 1
+	}
+
+	if cmd.args == "[city]" && len(args) > 0 {
+		flagsSettings[configKeyCity] = args[0]
+	}
+
+	cfg, err := getParams(flagsSettings)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	return cmd.run(cfg, args)
+}
+
+// ----------------------------------------------------------------------------
+// full forecast, default command
+func runForecast(cfg config, _ []string) int {
+	if inList(cfg.format, barFormats) {
+		return runBar(cfg)
+	}
+
+	forecastNow, forecastByHours, forecastNext := getWeather(&cfg)
+	convertForecastUnits(forecastNow, forecastByHours, forecastNext, cfg.units)
+	render(forecastNow, forecastByHours, forecastNext, cfg)
+
+	warnings, _ := forecastNow["warnings"].([]weatherWarning)
+	return alertExitCode(warnings, cfg)
+}
+
+// ----------------------------------------------------------------------------
+// setup color output and platform specific settings
+func setupTerminal(cfg *config) {
+	if cfg.noColor {
+		cfg.colorMode = colorModeNever
+	}
+	cfg.terminal = detectTerminal(cfg.colorMode)
+	cfg.noColor = !cfg.terminal.color
+	cfg.colorDepth = cfg.terminal.colorDepth
+
+	if runtime.GOOS == "windows" {
+		// broken unicode symbols in cmd.exe
+		cfg.noToday = true
+	}
+}
+
+// ----------------------------------------------------------------------------
+// man page in roff format
+func (cmd *command) manPage() string {
+	result := strings.Builder{}
+	result.WriteString(".TH \"" + manEscape(strings.ToUpper(appName)) + "\" \"1\" \"\" \"" + manEscape(version) + "\" \"\"\n")
+	result.WriteString(".SH \"NAME\"\n" + manEscape(appName) + " \\- " + manEscape(strings.ToLower(cmd.short[:1])+cmd.short[1:]) + "\n")
+	result.WriteString(".SH \"SYNOPSIS\"\n")
+	result.WriteString(manEscape(cmd.synopsis()) + "\n.br\n")
+	result.WriteString(manEscape(appName+" <command> [options] [args]") + "\n")
+	result.WriteString(".SH \"DESCRIPTION\"\n" + manEscape(cmd.long) + "\n")
+	result.WriteString(".SH \"OPTIONS\"\n")
+	result.WriteString(cmd.manOptions())
+
+	result.WriteString(".SH \"COMMANDS\"\n")
+	var walk func(c *command)
+	walk = func(c *command) {
+		for _, subCmd := range c.visibleCommands() {
+			result.WriteString(".SS \"" + manEscape(subCmd.fullName()) + "\"\n")
+			result.WriteString(manEscape(subCmd.synopsis()) + "\n.PP\n")
+			result.WriteString(manEscape(subCmd.long) + "\n")
+			if subCmd.flags != nil {
+				result.WriteString(".PP\nOwn options:\n")
+				result.WriteString(subCmd.manOptions())
+			}
+			walk(subCmd)
+		}
+	}
+	walk(cmd)
+
+	result.WriteString(".SH \"ENVIRONMENT\"\n")
+	for _, env := range envVars {
+		result.WriteString(".TP\n\\fB" + manEscape(env.name) + "\\fR\n" + manEscape(env.desc) + "\n")
+	}
+
+	result.WriteString(".SH \"FILES\"\n")
+	result.WriteString(".TP\n\\fB~/.config/" + appConfigDirName + "/" + configFileName + "\\fR\nconfig file with defaults, named profiles and custom color themes\n")
+
+	result.WriteString(".SH \"EXAMPLES\"\n.nf\n")
+	examples := []string{}
+	walk = func(c *command) {
+		for _, example := range c.examples {
+			examples = append(examples, strings.TrimSpace(appName+" "+example))
+		}
+		for _, subCmd := range c.visibleCommands() {
+			walk(subCmd)
+		}
+	}
+	walk(cmd)
+	for _, example := range examples {
+		result.WriteString(manEscape(example) + "\n")
+	}
+	result.WriteString(".fi\n")
+
+	result.WriteString(".SH \"SEE ALSO\"\nhttps://github.com/msoap/" + manEscape(appName) + "\n")
+
+	return result.String()
+}
+
+// ----------------------------------------------------------------------------
+// options of command in roff format, without common options for subcommands
+func (cmd *command) manOptions() string {
+	cfg := defaultConfig()
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	if cmd.parent == nil {
+		addCommonFlags(fs, &cfg)
+	}
+	if cmd.flags != nil {
+		cmd.flags(fs, &cfg)
+	}
+
+	names := []string{}
+	fs.VisitAll(func(f *flag.Flag) { names = append(names, f.Name) })
+	sort.Strings(names)
+
+	result := strings.Builder{}
+	for _, name := range names {
+		f := fs.Lookup(name)
+		argName, usage := flag.UnquoteUsage(f)
+		option := "\\fB\\-" + manEscape(name) + "\\fR"
+		if argName != "" {
+			option += " \\fI" + manEscape(argName) + "\\fR"
+		}
+		if f.DefValue != "" && f.DefValue != "false" && f.DefValue != "0" && f.DefValue != "0s" && name != "config" {
+			usage += " (default " + f.DefValue + ")"
+		}
+		result.WriteString(".TP\n" + option + "\n" + manEscape(usage) + "\n")
+	}
+
+	return result.String()
+}
+
+// ----------------------------------------------------------------------------
+// escape text for roff
+func manEscape(text string) string {
+	text = strings.Replace(text, "\\", "\\e", -1)
+	text = strings.Replace(text, "-", "\\-", -1)
+	if strings.HasPrefix(text, ".") || strings.HasPrefix(text, "'") {
+		text = "\\&" + text
+	}
+	return text
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"flag"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_parseArgs(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		wantCommand  string
+		wantSettings map[string]string
+		wantArgs     []string
+	}{
+		{
+			name:         "default command",
+			args:         []string{"-days", "3", "kyiv"},
+			wantCommand:  "",
+			wantSettings: map[string]string{"days": "3"},
+			wantArgs:     []string{"kyiv"},
+		},
+		{
+			name:         "subcommand with flags",
+			args:         []string{"hours", "-chart-height", "5", "-json", "london"},
+			wantCommand:  "hours",
+			wantSettings: map[string]string{"chart_height": "5", "json": "true"},
+			wantArgs:     []string{"london"},
+		},
+		{
+			name:         "flags before subcommand",
+			args:         []string{"-profile", "dacha", "config", "show"},
+			wantCommand:  "config show",
+			wantSettings: map[string]string{"profile": "dacha"},
+			wantArgs:     []string{},
+		},
+		{
+			name:         "help for command",
+			args:         []string{"help", "days"},
+			wantCommand:  "help",
+			wantSettings: map[string]string{},
+			wantArgs:     []string{"days"},
+		},
+	}
+
+	for _, tt := range tests {
+		cmd, settings, args := parseArgs(commandTree(), tt.args)
+		if cmd.fullName() != tt.wantCommand {
+			t.Errorf("%q. parseArgs() command = %q, want %q", tt.name, cmd.fullName(), tt.wantCommand)
+		}
+		if !reflect.DeepEqual(settings, tt.wantSettings) {
+			t.Errorf("%q. parseArgs() settings = %v, want %v", tt.name, settings, tt.wantSettings)
+		}
+		if !reflect.DeepEqual(args, tt.wantArgs) {
+			t.Errorf("%q. parseArgs() args = %v, want %v", tt.name, args, tt.wantArgs)
+		}
+	}
+}
+
+func Test_commandTree(t *testing.T) {
+	root := commandTree()
+
+	cmd, args := root.find([]string{"config", "show", "extra"})
+	if cmd.fullName() != "config show" || !reflect.DeepEqual(args, []string{"extra"}) {
+		t.Errorf("find() = %q, %v", cmd.fullName(), args)
+	}
+	if got := cmd.synopsis(); got != "yandex-weather-cli config show [options]" {
+		t.Errorf("synopsis() = %q", got)
+	}
+	if got := root.subCommand("config").synopsis(); got != "yandex-weather-cli config <command>" {
+		t.Errorf("synopsis() = %q", got)
+	}
+
+	// all configurable flags are in root command, for merge settings from config file
+	cfg := defaultConfig()
+	rootFlags := root.flagSet(&cfg)
+	var walk func(c *command)
+	walk = func(c *command) {
+		for _, subCmd := range c.commands {
+			if subCmd.run == nil && subCmd.flags != nil {
+				t.Errorf("command %q: group of commands with flags", subCmd.fullName())
+			}
+			cfg := defaultConfig()
+			fs := subCmd.flagSet(&cfg)
+			fs.VisitAll(func(f *flag.Flag) {
+				if rootFlags.Lookup(f.Name) == nil {
+					t.Errorf("command %q: flag %q is not in root command", subCmd.fullName(), f.Name)
+				}
+			})
+			walk(subCmd)
+		}
+	}
+	walk(root)
+}
+
+func Test_manPage(t *testing.T) {
+	man := commandTree().manPage()
+	for _, want := range []string{
+		`.TH "YANDEX\-WEATHER\-CLI" "1"`,
+		".SH \"COMMANDS\"\n",
+		`.SS "config show"`,
+		`\fB\-chart\-height\fR \fIint\fR`,
+		`\fBY_WEATHER_PROFILE\fR`,
+		`yandex\-weather\-cli days \-days 5 \-days\-chart kyiv`,
+	} {
+		if !strings.Contains(man, want) {
+			t.Errorf("manPage() don't contain %q", want)
+		}
+	}
+
+	if got := manEscape(`.start -a \b`); got != `\&.start \-a \eb` {
+		t.Errorf("manEscape() = %q", got)
+	}
+}
@@ -0,0 +1,264 @@
+// shell completion for bash, zsh and fish
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	// completeCommandName - hidden command for completion scripts, prints candidates for last word
+	completeCommandName = "__complete"
+	// completeFuncName - name of shell function for completion
+	completeFuncName = "_yandex_weather_cli"
+)
+
+// completionScripts - completion scripts for shells, call hidden completion command
+var completionScripts = map[string]string{
+	"bash": `# bash completion for {{app}}, usage: source <({{app}} completion bash)
+{{func}}() {
+    # words are split by whitespace only, COMP_WORDS are split by "=" and ":" from COMP_WORDBREAKS too
+    local line="${COMP_LINE:0:COMP_POINT}" IFS=$' \t\n'
+    local -a words
+    read -r -a words <<< "$line"
+    [[ "$line" == *[[:space:]] ]] && words+=("")
+    # bash replaces only part of current word after the last char from COMP_WORDBREAKS: "al" of "-color=al"
+    local cur="${words[${#words[@]}-1]}"
+    local prefix="${cur%"${cur##*[$COMP_WORDBREAKS]}"}"
+    IFS=$'\n'
+    COMPREPLY=($({{app}} {{complete}} "${words[@]:1}" 2>/dev/null))
+    COMPREPLY=("${COMPREPLY[@]#"$prefix"}")
+}
+complete -F {{func}} {{app}}
+`,
+	"zsh": `#compdef {{app}}
+# zsh completion for {{app}}, usage: source <({{app}} completion zsh)
+{{func}}() {
+    local -a candidates
+    candidates=("${(@f)$({{app}} {{complete}} "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+    [[ -n "$candidates" ]] && compadd -- $candidates
+}
+compdef {{func}} {{app}}
+`,
+	"fish": `# fish completion for {{app}}, usage: {{app}} completion fish | source
+function {{func}}
+    set -l tokens (commandline -opc) (commandline -ct)
+    {{app}} {{complete}} $tokens[2..-1] 2>/dev/null
+end
+complete -c {{app}} -f -a '({{func}})'
+`,
+}
+
+// flagValues - values of flags for completion
+var flagValues = map[string]func(cfg config) []string{
+	"color": func(config) []string { return []string{colorModeAuto, colorModeAlways, colorModeNever} },
+	"units": func(config) []string { return []string{unitsCelsius, unitsFahrenheit} },
+	"lang":  func(config) []string { return []string{langRu, langEn} },
+	"format": func(config) []string {
+		return []string{formatText, formatJSON, formatSummary, formatAccessible, formatOneline, formatWaybar, formatI3bar, formatPolybar}
+	},
+	"theme": func(config) []string {
+		return strings.Split(themesNames(), ", ")
+	},
+	"profile": func(cfg config) []string {
+		file, err := readConfigFile(cfg.configPath)
+		if err != nil {
+			return nil
+		}
+		return file.profiles()
+	},
+}
+
+// ----------------------------------------------------------------------------
+// completion script for shell
+func completionScript(shell string) (string, error) {
+	script, ok := completionScripts[shell]
+	if !ok {
+		return "", fmt.Errorf("unknown shell %q, want: %s", shell, strings.Join(completionShells(), ", "))
+	}
+
+	return strings.NewReplacer(
+		"{{app}}", appName,
+		"{{func}}", completeFuncName,
+		"{{complete}}", completeCommandName,
+	).Replace(script), nil
+}
+
+// ----------------------------------------------------------------------------
+// shells with completion scripts
+func completionShells() []string {
+	result := []string{}
+	for shell := range completionScripts {
+		result = append(result, shell)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// ----------------------------------------------------------------------------
+// candidates for completion of last word, words are args of command line without program name
+func completeWords(root *command, cfg config, words []string) []string {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	current, words := words[len(words)-1], words[:len(words)-1]
+
+	cmd := root
+	positional := []string{}
+	var valueFlag *flag.Flag
+	for _, word := range words {
+		fs := cmd.flagSet(&config{})
+		switch {
+		case valueFlag != nil:
+			valueFlag = nil
+		case strings.HasPrefix(word, "-") && len(word) > 1:
+			f := fs.Lookup(strings.TrimLeft(word, "-"))
+			if f != nil && !isBoolFlag(f) && !strings.Contains(word, "=") {
+				valueFlag = f
+			}
+		case len(positional) == 0 && cmd.subCommand(word) != nil:
+			cmd = cmd.subCommand(word)
+		default:
+			positional = append(positional, word)
+		}
+	}
+
+	candidates := []string{}
+	switch {
+	case valueFlag != nil:
+		if values, ok := flagValues[valueFlag.Name]; ok {
+			candidates = values(cfg)
+		}
+	case strings.HasPrefix(current, "-") && strings.Contains(current, "="):
+		parts := strings.SplitN(strings.TrimLeft(current, "-"), "=", 2)
+		if values, ok := flagValues[parts[0]]; ok {
+			prefix := current[:len(current)-len(parts[1])]
+			for _, value := range values(cfg) {
+				candidates = append(candidates, prefix+value)
+			}
+		}
+	case strings.HasPrefix(current, "-"):
+		cmd.flagSet(&config{}).VisitAll(func(f *flag.Flag) {
+			candidates = append(candidates, "-"+f.Name)
+		})
+	case cmd.args == "[city...]":
+		candidates = append(candidates, completeCities(cfg)...)
+	case len(positional) == 0:
+		for _, subCmd := range cmd.visibleCommands() {
+			candidates = append(candidates, subCmd.name)
+		}
+		switch cmd.args {
+		case "[city]":
+			candidates = append(candidates, completeCities(cfg)...)
+		case "[command]":
+			candidates = append(candidates, completionCommands(root)...)
+		case "<shell>":
+			candidates = append(candidates, completionShells()...)
+		}
+	}
+
+	return filterByPrefix(candidates, current)
+}
+
+// ----------------------------------------------------------------------------
+// names of all visible commands
+func completionCommands(root *command) []string {
+	result := []string{}
+	for _, cmd := range root.visibleCommands() {
+		result = append(result, cmd.name)
+	}
+	return result
+}
+
+// ----------------------------------------------------------------------------
+// cities for completion: favorites from config, recent from cache, cities from gazetteer
+func completeCities(cfg config) []string {
+	result := append([]string{}, cfg.favorites...)
+	result = append(result, recentCities(cfg)...)
+	return append(result, citySlugs(gazetteer)...)
+}
+
+// ----------------------------------------------------------------------------
+// cities from cached pages
+func recentCities(cfg config) []string {
+	dir, err := cacheDir()
+	if err != nil {
+		return nil
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].ModTime().After(files[j].ModTime()) })
+
+	result := []string{}
+	for _, file := range files {
+		pageURL, err := url.QueryUnescape(strings.TrimSuffix(file.Name(), cacheFileExt))
+		if err != nil {
+			continue
+		}
+		for _, baseURL := range []string{cfg.baseURL, cfg.baseURLMini} {
+			if city := strings.TrimPrefix(pageURL, baseURL); baseURL != "" && city != pageURL && city != "" && !strings.ContainsAny(city, "/?&") {
+				result = append(result, city)
+			}
+		}
+	}
+
+	return result
+}
+
+// ----------------------------------------------------------------------------
+// unique candidates with prefix, in original order
+func filterByPrefix(candidates []string, prefix string) []string {
+	result := []string{}
+	seen := map[string]bool{}
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, prefix) && !seen[candidate] {
+			seen[candidate] = true
+			result = append(result, candidate)
+		}
+	}
+
+	return result
+}
+
+// ----------------------------------------------------------------------------
+// check that flag don't need value
+func isBoolFlag(f *flag.Flag) bool {
+	boolFlag, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && boolFlag.IsBoolFlag()
+}
+
+// ----------------------------------------------------------------------------
+// print candidates for completion
+func runComplete(root *command, cfg config, args []string) int {
+	for _, candidate := range completeWords(root, cfg, args) {
+		fmt.Println(candidate)
+	}
+	return 0
+}
+
+// ----------------------------------------------------------------------------
+// print completion script
+func runCompletion(_ config, args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "shell is required: %s\n", strings.Join(completionShells(), ", "))
+		return 1
+	}
+
+	script, err := completionScript(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	fmt.Print(script)
+	return 0
+}
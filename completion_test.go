@@ -0,0 +1,209 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_completeWords(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "yandex-weather-cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir) // nolint: errcheck
+
+	configPath := filepath.Join(tmpDir, "config.toml")
+	if err := ioutil.WriteFile(configPath, []byte("[profile.home]\n[profile.dacha]\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := defaultConfig()
+	cfg.configPath = configPath
+	cfg.favorites = []string{"kyiv", "dacha-village"}
+
+	tests := []struct {
+		name  string
+		words []string
+		want  []string
+	}{
+		{"commands", []string{"h"}, []string{"hours", "help"}},
+		{"hidden command", []string{"__"}, []string{}},
+		{"favorites before known cities", []string{"k"}, []string{"kyiv", "kazan", "krasnoyarsk", "krasnodar", "kaliningrad", "khabarovsk", "kharkiv"}},
+		{"commands and favorites", []string{"da"}, []string{"days", "dacha-village"}},
+		{"flags of command", []string{"hours", "-b"}, []string{"-braille"}},
+		{"flags of root", []string{"-no-"}, []string{"-no-color", "-no-today"}},
+		{"flag value", []string{"-color", ""}, []string{"auto", "always", "never"}},
+		{"flag value with prefix", []string{"days", "-lang", "e"}, []string{"en"}},
+		{"flag with =", []string{"-units="}, []string{"-units=c", "-units=f"}},
+		{"profiles", []string{"-profile", ""}, []string{"dacha", "home"}},
+		{"after flag value", []string{"-days", "3", "mos"}, []string{"moscow"}},
+		{"after bool flag", []string{"-json", "lond"}, []string{"london"}},
+		{"subcommands", []string{"config", ""}, []string{"show"}},
+		{"help", []string{"help", "co"}, []string{"config", "completion"}},
+		{"shells", []string{"completion", ""}, []string{"bash", "fish", "zsh"}},
+		{"after city", []string{"kyiv", ""}, []string{}},
+		{"several cities", []string{"best-day", "kyiv", "mos"}, []string{"moscow"}},
+		{"empty", nil, nil},
+	}
+
+	for _, tt := range tests {
+		got := completeWords(commandTree(), cfg, tt.words)
+		if tt.want == nil {
+			if len(got) == 0 {
+				t.Errorf("%q. completeWords() is empty", tt.name)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q. completeWords(%q) = %q, want %q", tt.name, tt.words, got, tt.want)
+		}
+	}
+}
+
+func Test_recentCities(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "yandex-weather-cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir) // nolint: errcheck
+
+	oldCacheHome := os.Getenv("XDG_CACHE_HOME")
+	defer os.Setenv("XDG_CACHE_HOME", oldCacheHome) // nolint: errcheck
+	if err := os.Setenv("XDG_CACHE_HOME", tmpDir); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := defaultConfig()
+	for _, pageURL := range []string{cfg.baseURL + "tver", cfg.baseURLMini + "tver", cfg.baseURL + "moscow/details", "https://example.com/ryazan"} {
+		cacheFile, err := cachePath(pageURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := writeCache(cacheFile, []byte("html")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := filterByPrefix(recentCities(cfg), ""); !reflect.DeepEqual(got, []string{"tver"}) {
+		t.Errorf("recentCities() = %q", got)
+	}
+}
+
+func Test_completionScript(t *testing.T) {
+	for _, shell := range completionShells() {
+		script, err := completionScript(shell)
+		if err != nil {
+			t.Errorf("completionScript(%q) error: %s", shell, err)
+		}
+		if !strings.Contains(script, appName+" "+completeCommandName) || strings.Contains(script, "{{") {
+			t.Errorf("completionScript(%q) = %s", shell, script)
+		}
+	}
+
+	if _, err := completionScript("tcsh"); err == nil {
+		t.Errorf("completionScript() for unknown shell want error")
+	}
+}
+
+func Test_completionScriptSyntax(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "yandex-weather-cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir) // nolint: errcheck
+
+	checked := 0
+	for _, shell := range completionShells() {
+		shellPath, err := exec.LookPath(shell)
+		if err != nil {
+			t.Logf("%s not found, syntax of completion script isn't checked", shell)
+			continue
+		}
+
+		script, err := completionScript(shell)
+		if err != nil {
+			t.Fatal(err)
+		}
+		scriptPath := filepath.Join(tmpDir, "completion."+shell)
+		if err := ioutil.WriteFile(scriptPath, []byte(script), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		// -n - read commands without executing them
+		if out, err := exec.Command(shellPath, "-n", scriptPath).CombinedOutput(); err != nil {
+			t.Errorf("%s -n completion script: %s, %s", shell, err, out)
+		}
+		checked++
+	}
+
+	if checked == 0 {
+		t.Skip("no shells found")
+	}
+}
+
+// run program with args after "--" as helper process for completion scripts
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("YW_HELPER_PROCESS") != "1" {
+		return
+	}
+
+	args := os.Args
+	for i, arg := range args {
+		if arg == "--" {
+			args = args[i+1:]
+			break
+		}
+	}
+	os.Exit(runCommand(args))
+}
+
+func Test_completionScriptBash(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not found")
+	}
+
+	script, err := completionScript("bash")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"yandex-weather-cli ho", "hours"},
+		{"yandex-weather-cli hours -b", "-braille"},
+		{"yandex-weather-cli -color n", "never"},
+		{"yandex-weather-cli completion z", "zsh"},
+		// bash replaces part of word after "=" or ":" from COMP_WORDBREAKS
+		{"yandex-weather-cli -color=al", "always"},
+		{"yandex-weather-cli -format=wa", "waybar"},
+		{"yandex-weather-cli -units=f ho", "hours"},
+		{"yandex-weather-cli geo:55.9,36.8 -colo", "-color"},
+		{"yandex-weather-cli -lang=en days -detailed  -json", "-json"},
+	}
+
+	for _, tt := range tests {
+		cmd := exec.Command(bash, "--norc", "-c", script+`
+yandex-weather-cli() { YW_HELPER_PROCESS=1 "$TEST_BINARY" -test.run=TestHelperProcess -- "$@"; }
+COMP_LINE="$TEST_LINE"
+COMP_POINT=${#COMP_LINE}
+`+completeFuncName+`
+printf '%s\n' "${COMPREPLY[@]}"
+`)
+		cmd.Env = append(os.Environ(), "TEST_BINARY="+os.Args[0], "TEST_LINE="+tt.line, envConfigName+"=/nonexistent/config.toml")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Errorf("%s: %s, %s", tt.line, err, out)
+			continue
+		}
+		if got := strings.TrimSpace(string(out)); got != tt.want {
+			t.Errorf("bash completion %q = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
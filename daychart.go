@@ -0,0 +1,93 @@
+// day/night temperature range chart for forecast by days
+package main
+
+import "fmt"
+
+const (
+	// daysChartWidth - width of range bar for one day
+	daysChartWidth = 40
+	// daysChartBar - char for filled part of range bar
+	daysChartBar = "█"
+	// daysChartEmpty - char for empty part of range bar
+	daysChartEmpty = "·"
+	// daysChartLineWidth - width of days chart with date and temperatures columns
+	daysChartLineWidth = daysChartWidth + 23
+)
+
+// ----------------------------------------------------------------------------
+// min and max temperatures of all days, max always greater than min
+func daysTempsRange(forecastNext []dayForecast) (minTemp, maxTemp int) {
+	minTemp, maxTemp = forecastNext[0].TempNight, forecastNext[0].Temp
+	for _, row := range forecastNext {
+		for _, temp := range []int{row.Temp, row.TempNight} {
+			if minTemp > temp {
+				minTemp = temp
+			}
+			if maxTemp < temp {
+				maxTemp = temp
+			}
+		}
+	}
+
+	if maxTemp == minTemp {
+		maxTemp = minTemp + 1
+	}
+
+	return minTemp, maxTemp
+}
+
+// ----------------------------------------------------------------------------
+// Render range chart for forecast by days, one bar from night to day temperature
+// for each day on the shared scale. Lines returned with color tags, see ansiColourString().
+func renderDaysChart(forecastNext []dayForecast, today string, cfg config) []string {
+	if len(forecastNext) == 0 {
+		return nil
+	}
+
+	minTemp, maxTemp := daysTempsRange(forecastNext)
+	cellTemp := func(i int) float64 {
+		return float64(minTemp) + (float64(i)+0.5)/daysChartWidth*float64(maxTemp-minTemp)
+	}
+
+	minLabel, maxLabel := fmt.Sprintf("%d°", minTemp), fmt.Sprintf("%d°", maxTemp)
+	result := []string{
+		fmt.Sprintf("<header> %-10s %5s %s%*s</>",
+			cfg.tr("date"),
+			"",
+			minLabel,
+			daysChartWidth-len([]rune(minLabel)), maxLabel,
+		),
+	}
+
+	for _, row := range forecastNext {
+		fromTemp, toTemp := row.TempNight, row.Temp
+		if fromTemp > toTemp {
+			fromTemp, toTemp = toTemp, fromTemp
+		}
+
+		bar := ""
+		for i := 0; i < daysChartWidth; i++ {
+			if temp := cellTemp(i); temp >= float64(fromTemp)-0.5 && temp <= float64(toTemp)+0.5 {
+				bar += "<" + cfg.tempColor(temp) + ">" + daysChartBar + "</>"
+			} else {
+				bar += "<empty>" + daysChartEmpty + "</>"
+			}
+		}
+
+		todayMark := ""
+		if row.Date == today {
+			todayMark = " <today>" + cfg.tr("today") + "</>"
+		}
+
+		result = append(result, fmt.Sprintf(
+			" %10s %4d° %s %3d°%s",
+			cfg.weekendRe().ReplaceAllString(row.DateHuman, "<weekend>$1</>"),
+			fromTemp,
+			bar,
+			toTemp,
+			todayMark,
+		))
+	}
+
+	return result
+}
@@ -0,0 +1,33 @@
+package main
+
+import "testing"
+
+func Test_renderDaysChart(t *testing.T) {
+	forecastNext := []dayForecast{
+		{DateHuman: "20.10 (пн)", Date: "2021-10-20", Temp: 5, TempNight: -3},
+		{DateHuman: "21.10 (вт)", Date: "2021-10-21", Temp: 8, TempNight: 1},
+		{DateHuman: "24.10 (сб)", Date: "2021-10-24", Temp: -1, TempNight: -7},
+	}
+	cfg := config{noColor: true}
+
+	want := []string{
+		" дата             -7°                                   8°",
+		" 20.10 (пн)   -3° ·········████████████████████████·······   5°",
+		" 21.10 (вт)    1° ····················████████████████████   8° сегодня",
+		" 24.10 (сб)   -7° █████████████████·······················  -1°",
+	}
+
+	got := renderDaysChart(forecastNext, "2021-10-21", config{})
+	if len(got) != len(want) {
+		t.Fatalf("renderDaysChart() returned %d lines, want %d", len(got), len(want))
+	}
+	for i, line := range got {
+		if line = cfg.ansiColourString(line); line != want[i] {
+			t.Errorf("renderDaysChart() line %d = %q, want %q", i, line, want[i])
+		}
+	}
+
+	if got := renderDaysChart(nil, "", config{}); got != nil {
+		t.Errorf("renderDaysChart() without data = %v, want nil", got)
+	}
+}
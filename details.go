@@ -0,0 +1,256 @@
+// detailed forecast by days: morning, day, evening and night from details page
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/msoap/html2data"
+)
+
+// names of day parts for JSON, in order of details page
+const (
+	partMorning = "morning"
+	partDay     = "day"
+	partEvening = "evening"
+	partNight   = "night"
+)
+
+// dayPartsOrder - day parts in order of rows on details page
+var dayPartsOrder = []string{partMorning, partDay, partEvening, partNight}
+
+// dayPartNames - names of day parts on details page
+var dayPartNames = map[string]string{
+	"утром":   partMorning,
+	"днём":    partDay,
+	"днем":    partDay,
+	"вечером": partEvening,
+	"ночью":   partNight,
+	"morning": partMorning,
+	"day":     partDay,
+	"evening": partEvening,
+	"night":   partNight,
+}
+
+// selectorDetailsDay - root element of one day on details page
+var selectorDetailsDay = "article.card"
+
+// selectorsDetails - css selectors for day parts, one value for each row of day table
+var selectorsDetails = map[string]string{
+	"day":        "strong.forecast-details__day-number",
+	"part":       "tr.weather-table__row div.weather-table__daypart",
+	"temp":       "tr.weather-table__row div.weather-table__temp",
+	"desc":       "tr.weather-table__row td.weather-table__body-cell_type_condition",
+	"pressure":   "tr.weather-table__row td.weather-table__body-cell_type_air-pressure",
+	"humidity":   "tr.weather-table__row td.weather-table__body-cell_type_humidity",
+	"wind":       "tr.weather-table__row td.weather-table__body-cell_type_wind span.wind-speed",
+	"feels_like": "tr.weather-table__row td.weather-table__body-cell_type_feels-like span.temp__value",
+	"hour":       "div.forecast-details__hour span.forecast-details__hour-time",
+	"hour_temp":  "div.forecast-details__hour span.temp__value",
+	"hour_icon":  "div.forecast-details__hour i.icon:attr(class)",
+}
+
+// dayDetails - forecast of one day from details page
+type dayDetails struct {
+	Parts []dayPart
+	Hours []hourTemp
+}
+
+// dayPart - forecast for part of day
+type dayPart struct {
+	Name      string `json:"name"`
+	TempMin   int    `json:"temp_min"`
+	TempMax   int    `json:"temp_max"`
+	Desc      string `json:"desc"`
+	FeelsLike int    `json:"feels_like"`
+	Wind      string `json:"wind"`
+	Pressure  string `json:"pressure"`
+	Humidity  string `json:"humidity"`
+}
+
+// ----------------------------------------------------------------------------
+// URL of details page for city or coordinates
+func (cfg config) detailsURL() string {
+	if cfg.location != nil || cfg.city == "" {
+		return cfg.pageURL(cfg.baseURL + "details")
+	}
+
+	return cfg.baseURL + cfg.city + "/details"
+}
+
+// ----------------------------------------------------------------------------
+// parse day parts and hours from details page, by dates "2006-01-02"
+func parseDetails(doc html2data.Doc, cfg config) (map[string]dayDetails, error) {
+	days, err := doc.GetDataNested(selectorDetailsDay, selectorsDetails)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]dayDetails{}
+	for _, day := range days {
+		if len(day["day"]) == 0 {
+			continue
+		}
+		date, ok := dateByDayOfMonth(convertStrToInt(day["day"][0]), cfg.now())
+		if !ok {
+			continue
+		}
+
+		parts := []dayPart{}
+		for i, partName := range day["part"] {
+			column := func(name string) string {
+				if i < len(day[name]) {
+					return clearNonprintInString(day[name][i])
+				}
+				return ""
+			}
+
+			name, ok := dayPartNames[strings.ToLower(clearNonprintInString(partName))]
+			if !ok && i < len(dayPartsOrder) {
+				name = dayPartsOrder[i]
+			}
+			tempMin, tempMax := parseTempRange(column("temp"))
+			parts = append(parts, dayPart{
+				Name:      name,
+				TempMin:   tempMin,
+				TempMax:   tempMax,
+				Desc:      strings.ToLower(column("desc")),
+				FeelsLike: convertStrToInt(column("feels_like")),
+				Wind:      column("wind"),
+				Pressure:  column("pressure"),
+				Humidity:  column("humidity"),
+			})
+		}
+
+		hours := []hourTemp{}
+		for i, hour := range day["hour"] {
+			item := hourTemp{Date: date, Hour: convertStrToInt(hour)}
+			if i < len(day["hour_temp"]) {
+				item.Temp = convertStrToInt(day["hour_temp"][i])
+			}
+			if i < len(day["hour_icon"]) {
+				item.Icon = parseIcon(day["hour_icon"][i])
+			}
+			hours = append(hours, item)
+		}
+
+		result[date] = dayDetails{Parts: parts, Hours: hours}
+	}
+
+	return result, nil
+}
+
+// ----------------------------------------------------------------------------
+// parse range of temperatures: "+5…+7", "−3...−1" or "+5"
+func parseTempRange(text string) (int, int) {
+	text = strings.Replace(text, "...", "…", -1)
+	parts := strings.SplitN(text, "…", 2)
+	tempMin := convertStrToInt(parts[0])
+	if len(parts) == 1 {
+		return tempMin, tempMin
+	}
+
+	tempMax := convertStrToInt(parts[1])
+	if tempMin > tempMax {
+		tempMin, tempMax = tempMax, tempMin
+	}
+	return tempMin, tempMax
+}
+
+// ----------------------------------------------------------------------------
+// date "2006-01-02" for day of month from page, nearest to now: from yesterday to a month ahead
+func dateByDayOfMonth(day int, now time.Time) (string, bool) {
+	if day < 1 || day > 31 {
+		return "", false
+	}
+
+	for i := -1; i <= 31; i++ {
+		date := now.AddDate(0, 0, i)
+		if date.Day() == day {
+			return date.Format(dateLayout), true
+		}
+	}
+
+	return "", false
+}
+
+// ----------------------------------------------------------------------------
+// add day parts to forecast by days
+func addDayParts(forecastNext []dayForecast, details map[string]dayDetails) {
+	for i := range forecastNext {
+		if day, ok := details[forecastNext[i].Date]; ok {
+			forecastNext[i].Parts = day.Parts
+		}
+	}
+}
+
+// ----------------------------------------------------------------------------
+// forecast by hours for days from today, hours of each day have date of day
+func hoursFromDetails(details map[string]dayDetails, days int, cfg config) []hourTemp {
+	dates := []string{}
+	for date := range details {
+		if date >= cfg.today() && len(details[date].Hours) > 0 {
+			dates = append(dates, date)
+		}
+	}
+	sort.Strings(dates)
+
+	result := []hourTemp{}
+	for i := 0; i < len(dates) && i < days; i++ {
+		result = append(result, details[dates[i]].Hours...)
+	}
+
+	return result
+}
+
+// ----------------------------------------------------------------------------
+// split forecast by hours to days by date of hours, hours without date are one day
+func splitHoursByDay(forecastByHours []hourTemp) [][]hourTemp {
+	result := [][]hourTemp{}
+	for i, item := range forecastByHours {
+		if i == 0 || item.Date != forecastByHours[i-1].Date {
+			result = append(result, []hourTemp{})
+		}
+		result[len(result)-1] = append(result[len(result)-1], item)
+	}
+
+	return result
+}
+
+// ----------------------------------------------------------------------------
+// rows of nested table with day parts under row of day
+func renderDayParts(parts []dayPart, descLength int, cfg config) []string {
+	result := []string{}
+	for _, part := range parts {
+		temp := fmt.Sprintf("%d…%d°", part.TempMin, part.TempMax)
+		if part.TempMin == part.TempMax {
+			temp = fmt.Sprintf("%d°", part.TempMin)
+		}
+
+		props := nonEmpty(part.Wind, part.Pressure, part.Humidity)
+		result = append(result, strings.TrimRight(fmt.Sprintf(
+			"   <hours>%-8s</> <%s>%7s</> %-*s %s <%s>%d°</> %s",
+			cfg.tr("part_"+part.Name),
+			cfg.tempColor(float64(part.TempMin+part.TempMax)/2), temp,
+			descLength-8, part.Desc,
+			cfg.tr("feels_like"), cfg.tempColor(float64(part.FeelsLike)), part.FeelsLike,
+			strings.Join(props, ", "),
+		), " "))
+	}
+
+	return result
+}
+
+// ----------------------------------------------------------------------------
+// only not empty strings
+func nonEmpty(values ...string) []string {
+	result := []string{}
+	for _, value := range values {
+		if value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
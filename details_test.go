@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/msoap/html2data"
+)
+
+func Test_parseTempRange(t *testing.T) {
+	tests := []struct {
+		text             string
+		wantMin, wantMax int
+	}{
+		{"+5…+7", 5, 7},
+		{"−3...−1", -3, -1},
+		{"+2…−1", -1, 2},
+		{"+5", 5, 5},
+		{"", 0, 0},
+	}
+
+	for _, tt := range tests {
+		if gotMin, gotMax := parseTempRange(tt.text); gotMin != tt.wantMin || gotMax != tt.wantMax {
+			t.Errorf("parseTempRange(%q) = %d, %d, want %d, %d", tt.text, gotMin, gotMax, tt.wantMin, tt.wantMax)
+		}
+	}
+}
+
+func Test_dateByDayOfMonth(t *testing.T) {
+	now := time.Date(2021, 12, 30, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		day    int
+		want   string
+		wantOk bool
+	}{
+		{30, "2021-12-30", true},
+		{29, "2021-12-29", true},
+		{31, "2021-12-31", true},
+		{2, "2022-01-02", true},
+		{28, "2022-01-28", true},
+		{0, "", false},
+		{32, "", false},
+	}
+
+	for _, tt := range tests {
+		if got, ok := dateByDayOfMonth(tt.day, now); got != tt.want || ok != tt.wantOk {
+			t.Errorf("dateByDayOfMonth(%d) = %q, %v, want %q", tt.day, got, ok, tt.want)
+		}
+	}
+}
+
+func Test_parseDetails(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.clock = func() time.Time { return time.Date(2021, 10, 22, 15, 0, 0, 0, time.UTC) }
+
+	details, err := parseDetails(html2data.FromFile("testdata/moscow-details.html"), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(details) != 2 || len(details["2021-10-23"].Parts) != 4 || len(details["2021-10-24"].Parts) != 4 {
+		t.Fatalf("parseDetails() = %v", details)
+	}
+
+	want := dayPart{Name: partDay, TempMin: 8, TempMax: 9, Desc: "небольшой дождь", FeelsLike: 6, Wind: "4,2 м/с", Pressure: "745", Humidity: "78%"}
+	if got := details["2021-10-23"].Parts[1]; !reflect.DeepEqual(got, want) {
+		t.Errorf("parseDetails() day part = %+v, want %+v", got, want)
+	}
+
+	names := []string{}
+	for _, part := range details["2021-10-24"].Parts {
+		names = append(names, part.Name)
+	}
+	if !reflect.DeepEqual(names, dayPartsOrder) {
+		t.Errorf("parseDetails() names of parts = %q", names)
+	}
+
+	wantHour := hourTemp{Date: "2021-10-24", Hour: 21, Temp: 4, Icon: "icon_snow"}
+	if hours := details["2021-10-24"].Hours; len(hours) != 8 || !reflect.DeepEqual(hours[7], wantHour) {
+		t.Errorf("parseDetails() hours = %+v, want last %+v", hours, wantHour)
+	}
+
+	forecastNext := []dayForecast{{Date: "2021-10-23"}, {Date: "2021-10-25"}}
+	addDayParts(forecastNext, details)
+	if len(forecastNext[0].Parts) != 4 || forecastNext[1].Parts != nil {
+		t.Errorf("addDayParts() = %+v", forecastNext)
+	}
+}
+
+func Test_renderDayParts(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.lang = langEn
+	parts := []dayPart{
+		{Name: partMorning, TempMin: -2, TempMax: 1, Desc: "snow", FeelsLike: -6, Wind: "5 m/s", Humidity: "90%"},
+		{Name: partNight, TempMin: -4, TempMax: -4, Desc: "clear"},
+	}
+
+	got := renderDayParts(parts, 29, cfg)
+	want := []string{
+		"   <hours>morning </> <" + cfg.tempColor(-0.5) + ">  -2…1°</> snow                  feels like <" + cfg.tempColor(-6) + ">-6°</> 5 m/s, 90%",
+		"   <hours>night   </> <" + cfg.tempColor(-4) + ">    -4°</> clear                 feels like <" + cfg.tempColor(0) + ">0°</>",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("renderDayParts() =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func Test_hoursFromDetails(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.clock = func() time.Time { return time.Date(2021, 10, 23, 15, 0, 0, 0, time.UTC) }
+	details := map[string]dayDetails{
+		"2021-10-22": {Hours: []hourTemp{{Date: "2021-10-22", Hour: 0}}},
+		"2021-10-24": {Hours: []hourTemp{{Date: "2021-10-24", Hour: 0}, {Date: "2021-10-24", Hour: 12}}},
+		"2021-10-23": {Hours: []hourTemp{{Date: "2021-10-23", Hour: 12}}},
+		"2021-10-25": {Parts: []dayPart{{Name: partDay}}},
+		"2021-10-26": {Hours: []hourTemp{{Date: "2021-10-26", Hour: 0}}},
+	}
+
+	tests := []struct {
+		days int
+		want []string
+	}{
+		{1, []string{"2021-10-23 12"}},
+		{2, []string{"2021-10-23 12", "2021-10-24 0", "2021-10-24 12"}},
+		{5, []string{"2021-10-23 12", "2021-10-24 0", "2021-10-24 12", "2021-10-26 0"}},
+	}
+
+	for _, tt := range tests {
+		got := []string{}
+		for _, item := range hoursFromDetails(details, tt.days, cfg) {
+			got = append(got, fmt.Sprintf("%s %d", item.Date, item.Hour))
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("hoursFromDetails(%d) = %q, want %q", tt.days, got, tt.want)
+		}
+	}
+}
+
+func Test_splitHoursByDay(t *testing.T) {
+	tests := []struct {
+		name  string
+		hours []hourTemp
+		want  []int
+	}{
+		{"empty", nil, []int{}},
+		{"without dates", []hourTemp{{Hour: 1}, {Hour: 2}}, []int{2}},
+		{"days", []hourTemp{{Date: "2021-10-23"}, {Date: "2021-10-23"}, {Date: "2021-10-24"}, {Date: "2021-10-25"}}, []int{2, 1, 1}},
+	}
+
+	for _, tt := range tests {
+		got := []int{}
+		for _, day := range splitHoursByDay(tt.hours) {
+			got = append(got, len(day))
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q. splitHoursByDay() lengths = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+// extra panels of current weather: air quality, UV index, geomagnetic activity and water temperature
+package main
+
+import (
+	"fmt"
+
+	"github.com/msoap/html2data"
+)
+
+// selectorsExtra - css selectors for extra panels, yandex shows them not for every place
+var selectorsExtra = map[string]string{
+	"air_quality": "div.fact div.fact__props div.fact__air-quality",
+	"uv_index":    "div.fact div.fact__props div.fact__uv",
+	"geomagnetic": "div.fact div.fact__props div.fact__magnetic-field",
+	"water_temp":  "div.fact div.fact__props div.fact__water span.temp__value",
+}
+
+// extraPanels - numeric values of extra panels, nil if the page doesn't provide panel
+type extraPanels struct {
+	AirQuality  *int `json:"air_quality,omitempty"` // air quality index
+	UVIndex     *int `json:"uv_index,omitempty"`
+	Geomagnetic *int `json:"geomagnetic,omitempty"` // geomagnetic activity, Kp-index
+	WaterTemp   *int `json:"water_temp,omitempty"`
+}
+
+// ----------------------------------------------------------------------------
+// parse extra panels from main page, false if the page has none of them
+func parseExtraPanels(doc html2data.Doc) (extraPanels, bool) {
+	data, err := doc.GetDataFirst(selectorsExtra)
+	if err != nil {
+		return extraPanels{}, false
+	}
+
+	values := map[string]*int{}
+	for name := range selectorsExtra {
+		if number, ok := parseNumber(data[name]); ok {
+			value := int(number)
+			values[name] = &value
+		}
+	}
+	if len(values) == 0 {
+		return extraPanels{}, false
+	}
+
+	return extraPanels{
+		AirQuality:  values["air_quality"],
+		UVIndex:     values["uv_index"],
+		Geomagnetic: values["geomagnetic"],
+		WaterTemp:   values["water_temp"],
+	}, true
+}
+
+// ----------------------------------------------------------------------------
+// "Extra" block of current weather, without panels missing on the page
+func renderExtraPanels(extra extraPanels, cfg config) []string {
+	rows := []struct {
+		label string
+		value *int
+	}{
+		{"air_quality", extra.AirQuality},
+		{"uv_index", extra.UVIndex},
+		{"geomagnetic", extra.Geomagnetic},
+		{"water_temp", extra.WaterTemp},
+	}
+
+	result := []string{cfg.tr("extra") + ":"}
+	for _, row := range rows {
+		switch {
+		case row.value == nil:
+			continue
+		case row.label == "water_temp":
+			result = append(result, fmt.Sprintf("  %s: <%s>%d %s</>", cfg.tr(row.label), cfg.tempColor(float64(*row.value)), *row.value, cfg.tempUnit()))
+		default:
+			result = append(result, fmt.Sprintf("  %s: <value>%d</>", cfg.tr(row.label), *row.value))
+		}
+	}
+
+	return result
+}
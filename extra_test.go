@@ -0,0 +1,66 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/msoap/html2data"
+)
+
+func Test_parseExtraPanels(t *testing.T) {
+	intPtr := func(value int) *int { return &value }
+
+	tests := []struct {
+		name   string
+		html   string
+		want   extraPanels
+		wantOk bool
+	}{
+		{
+			name: "all panels",
+			html: `<div class="fact"><div class="fact__props">
+				<div class="fact__air-quality">Качество воздуха: 42</div>
+				<div class="fact__uv">УФ-индекс: 6, высокий</div>
+				<div class="fact__magnetic-field">Магнитное поле: слабая буря, 5 баллов</div>
+				<div class="fact__water">Вода: <span class="temp__value">+21</span></div>
+			</div></div>`,
+			want:   extraPanels{AirQuality: intPtr(42), UVIndex: intPtr(6), Geomagnetic: intPtr(5), WaterTemp: intPtr(21)},
+			wantOk: true,
+		},
+		{
+			name: "cold water only",
+			html: `<div class="fact"><div class="fact__props">
+				<div class="fact__water">Вода: <span class="temp__value">−1</span></div>
+			</div></div>`,
+			want:   extraPanels{WaterTemp: intPtr(-1)},
+			wantOk: true,
+		},
+		{
+			name: "no panels",
+			html: `<div class="fact"><div class="fact__props"><div class="fact__humidity">71%</div></div></div>`,
+		},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseExtraPanels(html2data.FromReader(strings.NewReader(tt.html)))
+		if ok != tt.wantOk || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q. parseExtraPanels() = %+v, %v, want %+v, %v", tt.name, got, ok, tt.want, tt.wantOk)
+		}
+	}
+}
+
+func Test_renderExtraPanels(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.noColor = true
+	uvIndex, waterTemp := 3, 18
+
+	got := renderExtraPanels(extraPanels{UVIndex: &uvIndex, WaterTemp: &waterTemp}, cfg)
+	for i := range got {
+		got[i] = cfg.ansiColourString(got[i])
+	}
+	want := []string{"Дополнительно:", "  УФ-индекс: 3", "  температура воды: 18 °C"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("renderExtraPanels() = %q, want %q", got, want)
+	}
+}
@@ -1,11 +1,11 @@
 module github.com/msoap/yandex-weather-cli
 
-go 1.13
+go 1.15
 
 require (
 	github.com/PuerkitoBio/goquery v1.7.0 // indirect
 	github.com/mattn/go-colorable v0.1.8
 	github.com/mgutz/ansi v0.0.0-20200706080929-d51e80ef957d
 	github.com/msoap/html2data v1.2.2
-	golang.org/x/net v0.0.0-20210614182718-04defd469f4e // indirect
+	golang.org/x/net v0.0.0-20210614182718-04defd469f4e
 )
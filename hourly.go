@@ -0,0 +1,122 @@
+// extra fields of forecast by hours: precipitation, wind and feels-like temperature
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// reNumber - number in text, with decimal comma or point
+var reNumber = regexp.MustCompile(`-?\d+(?:[.,]\d+)?`)
+
+// hourlyExtraRow - extra row under forecast by hours
+type hourlyExtraRow struct {
+	label  string
+	exists func(item hourTemp) bool
+	cell   func(item hourTemp, cfg config) string
+}
+
+// hourlyExtraRows - extra rows in order of output, row is shown if any hour has value
+var hourlyExtraRows = []hourlyExtraRow{
+	{
+		label:  "precip_prob",
+		exists: func(item hourTemp) bool { return item.PrecipProb != nil },
+		cell: func(item hourTemp, cfg config) string {
+			return fmt.Sprintf("<value>%3d%%</>", *item.PrecipProb)
+		},
+	},
+	{
+		label:  "precip",
+		exists: func(item hourTemp) bool { return item.Precip != nil },
+		cell: func(item hourTemp, cfg config) string {
+			return fmt.Sprintf("<value>%3s</> ", formatAmount(*item.Precip))
+		},
+	},
+	{
+		label:  "wind_speed",
+		exists: func(item hourTemp) bool { return item.Wind != nil },
+		cell: func(item hourTemp, cfg config) string {
+			return fmt.Sprintf("<value>%3s</> ", formatAmount(*item.Wind))
+		},
+	},
+	{
+		label:  "feels_like",
+		exists: func(item hourTemp) bool { return item.FeelsLike != nil },
+		cell: func(item hourTemp, cfg config) string {
+			return fmt.Sprintf("<%s>%3d°</>", cfg.tempColor(float64(*item.FeelsLike)), *item.FeelsLike)
+		},
+	},
+}
+
+// ----------------------------------------------------------------------------
+// parse extra fields of hour from row of mini page, missing fields are nil
+func parseHourExtras(item *hourTemp, row map[string]string) {
+	if value, ok := parseNumber(row["precip_prob"]); ok {
+		prob := int(value)
+		item.PrecipProb = &prob
+	}
+	if value, ok := parseNumber(row["precip"]); ok {
+		item.Precip = &value
+	}
+	if value, ok := parseNumber(row["wind"]); ok {
+		item.Wind = &value
+	}
+	if value, ok := parseNumber(row["feels_like"]); ok {
+		feelsLike := int(value)
+		item.FeelsLike = &feelsLike
+	}
+}
+
+// ----------------------------------------------------------------------------
+// first number in text: "0,5 мм" -> 0.5
+func parseNumber(text string) (float64, bool) {
+	text = strings.Replace(clearNonprintInString(text), "−", "-", -1)
+	match := reNumber.FindString(text)
+	if match == "" {
+		return 0, false
+	}
+
+	value, err := strconv.ParseFloat(strings.Replace(match, ",", ".", 1), 64)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+// ----------------------------------------------------------------------------
+// amount in 3 chars: 0.5, 12
+func formatAmount(value float64) string {
+	if value < 10 && value != float64(int(value)) {
+		return strconv.FormatFloat(value, 'f', 1, 64)
+	}
+	return strconv.FormatFloat(value, 'f', 0, 64)
+}
+
+// ----------------------------------------------------------------------------
+// extra rows under forecast by hours with label at the end, only rows with values
+func renderHourlyExtras(forecastByHours []hourTemp, cfg config) []string {
+	result := []string{}
+	for _, row := range hourlyExtraRows {
+		exists := false
+		for _, item := range forecastByHours {
+			exists = exists || row.exists(item)
+		}
+		if !exists {
+			continue
+		}
+
+		line := ""
+		for _, item := range forecastByHours {
+			if row.exists(item) {
+				line += row.cell(item, cfg)
+			} else {
+				line += "<empty>  - </>"
+			}
+		}
+		result = append(result, line+"<hours>"+cfg.tr(row.label)+"</>")
+	}
+
+	return result
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func Test_parseNumber(t *testing.T) {
+	tests := []struct {
+		text   string
+		want   float64
+		wantOk bool
+	}{
+		{"0,5 мм", 0.5, true},
+		{"1.25", 1.25, true},
+		{"70%", 70, true},
+		{"5 м/с, СЗ", 5, true},
+		{"−3°", -3, true},
+		{"+12", 12, true},
+		{"", 0, false},
+		{"нет", 0, false},
+	}
+
+	for _, tt := range tests {
+		if got, ok := parseNumber(tt.text); got != tt.want || ok != tt.wantOk {
+			t.Errorf("parseNumber(%q) = %v, %v, want %v, %v", tt.text, got, ok, tt.want, tt.wantOk)
+		}
+	}
+}
+
+func Test_formatAmount(t *testing.T) {
+	tests := []struct {
+		value float64
+		want  string
+	}{
+		{0, "0"},
+		{0.55, "0.6"},
+		{3, "3"},
+		{12.4, "12"},
+		{120, "120"},
+	}
+
+	for _, tt := range tests {
+		if got := formatAmount(tt.value); got != tt.want {
+			t.Errorf("formatAmount(%v) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func Test_parseHourExtras(t *testing.T) {
+	item := hourTemp{Hour: 10, Temp: 5}
+	parseHourExtras(&item, map[string]string{"precip_prob": "40%", "precip": "0,3 мм", "wind": "", "feels_like": "+2"})
+
+	jsonBytes, err := json.Marshal(item)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"hour":10,"temp":5,"icon":"","precip_prob":40,"precip":0.3,"feels_like":2}`
+	if string(jsonBytes) != want {
+		t.Errorf("parseHourExtras() = %s, want %s", jsonBytes, want)
+	}
+}
+
+func Test_renderHourlyExtras(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.lang = langEn
+	prob, wind := 30, 4.0
+
+	tests := []struct {
+		name            string
+		forecastByHours []hourTemp
+		want            []string
+	}{
+		{
+			name:            "without extra fields",
+			forecastByHours: []hourTemp{{Hour: 1}, {Hour: 2}},
+			want:            []string{},
+		},
+		{
+			name:            "rows with values",
+			forecastByHours: []hourTemp{{Hour: 1, PrecipProb: &prob}, {Hour: 2, PrecipProb: &prob, Wind: &wind}},
+			want: []string{
+				"<value> 30%</><value> 30%</><hours>precipitation probability</>",
+				"<empty>  - </><value>  4</> <hours>wind, m/s</>",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := renderHourlyExtras(tt.forecastByHours, cfg); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q. renderHourlyExtras() =\n%q\nwant\n%q", tt.name, got, tt.want)
+		}
+	}
+}
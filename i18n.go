@@ -0,0 +1,382 @@
+// languages and units of output
+package main
+
+import (
+	"math"
+	"regexp"
+)
+
+// languages for -lang option
+const (
+	langRu = "ru"
+	langEn = "en"
+)
+
+// temperature units for -units option
+const (
+	unitsCelsius    = "c"
+	unitsFahrenheit = "f"
+)
+
+// weekdays - short names of weekdays by language, from Sunday
+var weekdays = map[string][7]string{
+	langRu: {"вс", "пн", "вт", "ср", "чт", "пт", "сб"},
+	langEn: {"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+}
+
+// weekdaysUntil - names of weekdays for "dry until ..." by language, from Sunday
+var weekdaysUntil = map[string][7]string{
+	langRu: {"воскресенья", "понедельника", "вторника", "среды", "четверга", "пятницы", "субботы"},
+	langEn: {"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+}
+
+// weekdaysFull - full names of weekdays by language, from Sunday
+var weekdaysFull = map[string][7]string{
+	langRu: {"воскресенье", "понедельник", "вторник", "среда", "четверг", "пятница", "суббота"},
+	langEn: {"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+}
+
+// moonPhaseNames - names of moon phases by language
+var moonPhaseNames = map[string]map[string]string{
+	langRu: {
+		"new_moon":        "новолуние",
+		"waxing_crescent": "растущий серп",
+		"first_quarter":   "первая четверть",
+		"waxing_gibbous":  "растущая луна",
+		"full_moon":       "полнолуние",
+		"waning_gibbous":  "убывающая луна",
+		"last_quarter":    "последняя четверть",
+		"waning_crescent": "убывающий серп",
+	},
+	langEn: {
+		"new_moon":        "new moon",
+		"waxing_crescent": "waxing crescent",
+		"first_quarter":   "first quarter",
+		"waxing_gibbous":  "waxing gibbous",
+		"full_moon":       "full moon",
+		"waning_gibbous":  "waning gibbous",
+		"last_quarter":    "last quarter",
+		"waning_crescent": "waning crescent",
+	},
+}
+
+// unitNames - spelled out units by language, forms for 1, 2 and 5
+var unitNames = map[string]map[string][3]string{
+	langRu: {
+		"celsius":    {"градус Цельсия", "градуса Цельсия", "градусов Цельсия"},
+		"fahrenheit": {"градус Фаренгейта", "градуса Фаренгейта", "градусов Фаренгейта"},
+		"percent":    {"процент", "процента", "процентов"},
+		"mm":         {"миллиметр", "миллиметра", "миллиметров"},
+		"mmhg":       {"миллиметр ртутного столба", "миллиметра ртутного столба", "миллиметров ртутного столба"},
+		"mps":        {"метр в секунду", "метра в секунду", "метров в секунду"},
+		"hour":       {"час", "часа", "часов"},
+		"minute":     {"минута", "минуты", "минут"},
+	},
+	langEn: {
+		"celsius":    {"degree Celsius", "degrees Celsius", "degrees Celsius"},
+		"fahrenheit": {"degree Fahrenheit", "degrees Fahrenheit", "degrees Fahrenheit"},
+		"percent":    {"percent", "percent", "percent"},
+		"mm":         {"millimeter", "millimeters", "millimeters"},
+		"mmhg":       {"millimeter of mercury", "millimeters of mercury", "millimeters of mercury"},
+		"mps":        {"meter per second", "meters per second", "meters per second"},
+		"hour":       {"hour", "hours", "hours"},
+		"minute":     {"minute", "minutes", "minutes"},
+	},
+}
+
+// messages - labels of output by language
+var messages = map[string]map[string]string{
+	langRu: {
+		"now":                "Сейчас",
+		"pressure":           "Давление",
+		"humidity":           "Влажность",
+		"wind":               "Ветер",
+		"date":               "дата",
+		"weather":            "погода",
+		"night":              "ночью",
+		"today":              "сегодня",
+		"part_morning":       "утром",
+		"part_day":           "днём",
+		"part_evening":       "вечером",
+		"part_night":         "ночью",
+		"feels_like":         "ощущается",
+		"precip_prob":        "вероятность осадков",
+		"precip":             "осадки, мм",
+		"wind_speed":         "ветер, м/с",
+		"comfort":            "Комфорт",
+		"twilight":           "сумерки",
+		"extra":              "Дополнительно",
+		"air_quality":        "качество воздуха",
+		"uv_index":           "УФ-индекс",
+		"geomagnetic":        "геомагнитная активность",
+		"water_temp":         "температура воды",
+		"sun":                "солнце",
+		"day_length":         "день",
+		"moon":               "луна",
+		"dew_point":          "точка росы",
+		"humidex":            "хьюмидекс",
+		"heat_index":         "индекс жары",
+		"wind_chill":         "ветро-холодовой индекс",
+		"tomorrow":           "завтра",
+		"then":               "затем",
+		"rain_now":           "дождь идёт сейчас",
+		"rain_until":         "до %s",
+		"rain_hours":         "ближайшие %d ч",
+		"rain_from":          "дождь с %s",
+		"rain_from_to":       "дождь с %s до %s",
+		"no_rain_hours":      "без дождя ближайшие %d ч",
+		"rain_tomorrow":      "дождь завтра",
+		"dry_until":          "сухо до %s",
+		"dry_forecast":       "без дождя в прогнозе до %s",
+		"score_temp_in":      "температура %d° в диапазоне %d..%d°",
+		"score_temp_below":   "температура %d°, на %d° ниже %d..%d°",
+		"score_temp_above":   "температура %d°, на %d° выше %d..%d°",
+		"score_dry":          "без осадков: %s",
+		"score_precip":       "осадки: %s",
+		"score_wind_ok":      "ветер до %s м/с, не больше %d м/с",
+		"score_wind_over":    "ветер до %s м/с, больше %d м/с",
+		"score_wind_unknown": "ветер неизвестен",
+		"score_weekend":      "выходной",
+		"score_weekday":      "будний день",
+		"and":                "и",
+		"summary_now":        "сейчас %s, %s",
+		"summary_now_temp":   "сейчас %s",
+		"summary_warming":    "потепление до %s к %s",
+		"summary_cooling":    "похолодание до %s к %s",
+		"summary_steady":     "без резких перемен температуры в ближайшие часы",
+		"summary_rain":       "дождь %s",
+		"summary_snow":       "снег %s",
+		"summary_frost":      "заморозки %s ночью",
+		"summary_dry":        "без осадков в ближайшие дни",
+		"place":              "Место",
+		"warning_level":      "Предупреждение, %s уровень",
+		"warning_severity":   "%s уровень",
+		"severity_yellow":    "жёлтый",
+		"severity_orange":    "оранжевый",
+		"severity_red":       "красный",
+		"nowcast":            "Ближайшие часы",
+		"hours_forecast":     "Прогноз по часам",
+		"days_forecast":      "Прогноз по дням",
+		"trend":              "Ход температуры",
+		"trend_start":        "%s в %s",
+		"trend_rises":        "поднимается до отметки в %s к %s",
+		"trend_falls":        "опускается до отметки в %s к %s",
+		"trend_steady":       "без заметных изменений, около %s",
+		"weekend":            "выходной",
+		"minus":              "минус",
+		"precip_amount":      "осадки",
+		"temp_range":         "минимум %s, максимум %s",
+		"civil_twilight":     "сумерки с %s до %s",
+		"sunrise":            "восход %s",
+		"sunset":             "закат %s",
+		"day_length_text":    "долгота дня %s",
+		"moon_illuminated":   "освещённость %s",
+		"polar_day":          "полярный день",
+		"polar_night":        "полярная ночь",
+		"icon_rain":          "дождь",
+		"icon_snow":          "снег",
+	},
+	langEn: {
+		"now":                "Now",
+		"pressure":           "Pressure",
+		"humidity":           "Humidity",
+		"wind":               "Wind",
+		"date":               "date",
+		"weather":            "weather",
+		"night":              "night",
+		"today":              "today",
+		"part_morning":       "morning",
+		"part_day":           "day",
+		"part_evening":       "evening",
+		"part_night":         "night",
+		"feels_like":         "feels like",
+		"precip_prob":        "precipitation probability",
+		"precip":             "precipitation, mm",
+		"wind_speed":         "wind, m/s",
+		"comfort":            "Comfort",
+		"twilight":           "twilight",
+		"extra":              "Extra",
+		"air_quality":        "air quality",
+		"uv_index":           "UV index",
+		"geomagnetic":        "geomagnetic activity",
+		"water_temp":         "water temperature",
+		"sun":                "sun",
+		"day_length":         "day",
+		"moon":               "moon",
+		"dew_point":          "dew point",
+		"humidex":            "humidex",
+		"heat_index":         "heat index",
+		"wind_chill":         "wind chill",
+		"tomorrow":           "tomorrow",
+		"then":               "then",
+		"rain_now":           "rain now",
+		"rain_until":         "until %s",
+		"rain_hours":         "for next %d hours",
+		"rain_from":          "rain from %s",
+		"rain_from_to":       "rain from %s to %s",
+		"no_rain_hours":      "no rain in next %d hours",
+		"rain_tomorrow":      "rain tomorrow",
+		"dry_until":          "dry until %s",
+		"dry_forecast":       "no rain in forecast until %s",
+		"score_temp_in":      "temperature %d° in range %d..%d°",
+		"score_temp_below":   "temperature %d°, %d° below %d..%d°",
+		"score_temp_above":   "temperature %d°, %d° above %d..%d°",
+		"score_dry":          "no precipitation: %s",
+		"score_precip":       "precipitation: %s",
+		"score_wind_ok":      "wind up to %s m/s, limit %d m/s",
+		"score_wind_over":    "wind up to %s m/s, over limit %d m/s",
+		"score_wind_unknown": "wind unknown",
+		"score_weekend":      "weekend",
+		"score_weekday":      "weekday",
+		"and":                "and",
+		"summary_now":        "now %s and %s",
+		"summary_now_temp":   "now %s",
+		"summary_warming":    "warming to %s by %s",
+		"summary_cooling":    "cooling to %s by %s",
+		"summary_steady":     "steady temperature in the next hours",
+		"summary_rain":       "rain %s",
+		"summary_snow":       "snow %s",
+		"summary_frost":      "frost %s night",
+		"summary_dry":        "no precipitation in the coming days",
+		"place":              "Place",
+		"warning_level":      "Warning, %s level",
+		"warning_severity":   "%s level",
+		"severity_yellow":    "yellow",
+		"severity_orange":    "orange",
+		"severity_red":       "red",
+		"nowcast":            "Nowcast",
+		"hours_forecast":     "Forecast by hours",
+		"days_forecast":      "Forecast by days",
+		"trend":              "Temperature trend",
+		"trend_start":        "%s at %s",
+		"trend_rises":        "rises to %s by %s",
+		"trend_falls":        "falls to %s by %s",
+		"trend_steady":       "steady, about %s",
+		"weekend":            "weekend",
+		"minus":              "minus",
+		"precip_amount":      "precipitation",
+		"temp_range":         "from %s to %s",
+		"civil_twilight":     "civil twilight from %s to %s",
+		"sunrise":            "sunrise %s",
+		"sunset":             "sunset %s",
+		"day_length_text":    "day length %s",
+		"moon_illuminated":   "%s illuminated",
+		"polar_day":          "polar day",
+		"polar_night":        "polar night",
+		"icon_rain":          "rain",
+		"icon_snow":          "snow",
+	},
+}
+
+// weekendRes - regexps for weekend days in human date by language
+var weekendRes = map[string]*regexp.Regexp{}
+
+func init() {
+	for lang, names := range weekdays {
+		weekendRes[lang] = regexp.MustCompile(`(` + names[6] + `|` + names[0] + `)`)
+	}
+}
+
+// ----------------------------------------------------------------------------
+// translate label to language from config, Russian by default
+func (cfg config) tr(key string) string {
+	if text, ok := messages[cfg.lang][key]; ok {
+		return text
+	}
+	return messages[langRu][key]
+}
+
+// ----------------------------------------------------------------------------
+// name of moon phase in language from config, Russian by default
+func (cfg config) moonPhaseName(phase string) string {
+	if names, ok := moonPhaseNames[cfg.lang]; ok {
+		return names[phase]
+	}
+	return moonPhaseNames[langRu][phase]
+}
+
+// ----------------------------------------------------------------------------
+// regexp for weekend days in human date for language from config
+func (cfg config) weekendRe() *regexp.Regexp {
+	if re, ok := weekendRes[cfg.lang]; ok {
+		return re
+	}
+	return weekendRes[langRu]
+}
+
+// ----------------------------------------------------------------------------
+// symbol of temperature units
+func (cfg config) tempUnit() string {
+	if cfg.units == unitsFahrenheit {
+		return "°F"
+	}
+	return "°C"
+}
+
+// ----------------------------------------------------------------------------
+// color of temperature in units from config on theme gradient
+func (cfg config) tempColor(temp float64) string {
+	return cfg.getTheme().tempColor(cfg.toCelsius(temp))
+}
+
+// ----------------------------------------------------------------------------
+// convert temperature from units of config to Celsius
+func (cfg config) toCelsius(temp float64) float64 {
+	if cfg.units == unitsFahrenheit {
+		return (temp - 32) * 5 / 9
+	}
+	return temp
+}
+
+// ----------------------------------------------------------------------------
+// convert temperature from Celsius to units
+func convertTemp(temp int, units string) int {
+	if units == unitsFahrenheit {
+		return int(math.Round(float64(temp)*9/5 + 32))
+	}
+	return temp
+}
+
+// ----------------------------------------------------------------------------
+// convert all temperatures of forecast from Celsius to units
+func convertForecastUnits(forecastNow map[string]interface{}, forecastByHours []hourTemp, forecastNext []dayForecast, units string) {
+	if units == unitsCelsius {
+		return
+	}
+
+	if temp, ok := forecastNow["term_now"].(int); ok {
+		forecastNow["term_now"] = convertTemp(temp, units)
+	}
+	if comfort, ok := forecastNow["comfort"].(comfortMetrics); ok {
+		comfort.DewPoint = convertTemp(comfort.DewPoint, units)
+		comfort.Humidex = convertTemp(comfort.Humidex, units)
+		for _, value := range []*int{comfort.HeatIndex, comfort.WindChill} {
+			if value != nil {
+				*value = convertTemp(*value, units)
+			}
+		}
+		forecastNow["comfort"] = comfort
+	}
+	if extra, ok := forecastNow["extra"].(extraPanels); ok && extra.WaterTemp != nil {
+		waterTemp := convertTemp(*extra.WaterTemp, units)
+		extra.WaterTemp = &waterTemp
+		forecastNow["extra"] = extra
+	}
+	for i := range forecastByHours {
+		forecastByHours[i].Temp = convertTemp(forecastByHours[i].Temp, units)
+		if feelsLike := forecastByHours[i].FeelsLike; feelsLike != nil {
+			converted := convertTemp(*feelsLike, units)
+			forecastByHours[i].FeelsLike = &converted
+		}
+	}
+	for i := range forecastNext {
+		forecastNext[i].Temp = convertTemp(forecastNext[i].Temp, units)
+		forecastNext[i].TempNight = convertTemp(forecastNext[i].TempNight, units)
+		for j := range forecastNext[i].Parts {
+			part := &forecastNext[i].Parts[j]
+			part.TempMin = convertTemp(part.TempMin, units)
+			part.TempMax = convertTemp(part.TempMax, units)
+			part.FeelsLike = convertTemp(part.FeelsLike, units)
+		}
+	}
+}
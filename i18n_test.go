@@ -0,0 +1,65 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_convertForecastUnits(t *testing.T) {
+	forecastNow := map[string]interface{}{"term_now": -40}
+	forecastByHours := []hourTemp{{Hour: 1, Temp: 0}}
+	forecastNext := []dayForecast{{Temp: 100, TempNight: -18, Parts: []dayPart{{TempMin: 0, TempMax: 10, FeelsLike: -10}}}}
+
+	convertForecastUnits(forecastNow, forecastByHours, forecastNext, unitsFahrenheit)
+	if forecastNow["term_now"] != -40 || forecastByHours[0].Temp != 32 || forecastNext[0].Temp != 212 || forecastNext[0].TempNight != 0 {
+		t.Errorf("convertForecastUnits() = %v, %v, %v", forecastNow, forecastByHours, forecastNext)
+	}
+	if part := forecastNext[0].Parts[0]; part.TempMin != 32 || part.TempMax != 50 || part.FeelsLike != 14 {
+		t.Errorf("convertForecastUnits() day part = %+v", part)
+	}
+
+	convertForecastUnits(forecastNow, forecastByHours, forecastNext, unitsCelsius)
+	if forecastByHours[0].Temp != 32 {
+		t.Errorf("convertForecastUnits() for celsius changed data: %v", forecastByHours)
+	}
+}
+
+func Test_toCelsius(t *testing.T) {
+	tests := []struct {
+		units string
+		temp  float64
+		want  float64
+	}{
+		{unitsCelsius, -5, -5},
+		{unitsFahrenheit, 32, 0},
+		{unitsFahrenheit, -40, -40},
+		{unitsFahrenheit, 212, 100},
+	}
+
+	for _, tt := range tests {
+		if got := (config{units: tt.units}).toCelsius(tt.temp); got != tt.want {
+			t.Errorf("toCelsius(%v) in %q = %v, want %v", tt.temp, tt.units, got, tt.want)
+		}
+	}
+}
+
+func Test_formatDates(t *testing.T) {
+	date := time.Date(2021, 10, 23, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		lang, wantHuman string
+	}{
+		{langRu, "23.10 (сб)"},
+		{langEn, "23.10 (Sat)"},
+		{"", "23.10 (сб)"},
+	}
+
+	for _, tt := range tests {
+		human, jsonDate := formatDates(date, tt.lang)
+		if human != tt.wantHuman || jsonDate != "2021-10-23" {
+			t.Errorf("formatDates(%q) = %q, %q", tt.lang, human, jsonDate)
+		}
+		if !(config{lang: tt.lang}).weekendRe().MatchString(human) {
+			t.Errorf("weekendRe(%q) don't match %q", tt.lang, human)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+// location by coordinates: -lat/-lon options and geo: URI
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// geoURIScheme - scheme of geo URI (RFC 5870): "geo:55.75,37.61"
+const geoURIScheme = "geo:"
+
+// geoPoint - coordinates of location
+type geoPoint struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// reTitlePlace - name of place in title of yandex page: "Погода в Истре — Яндекс Погода"
+var reTitlePlace = regexp.MustCompile(`^(?:Погода|Weather)\s+(?:во|в|на|in)\s+(.+?)(?:\s+(?:на|сегодня|завтра|for|today)(?:\s.*)?|\s+[—–|].*)?$`)
+
+// ----------------------------------------------------------------------------
+// parse latitude and longitude, check ranges
+func parseGeoPoint(lat, lon string) (geoPoint, error) {
+	latitude, err := strconv.ParseFloat(strings.TrimSpace(lat), 64)
+	if err != nil || latitude < -90 || latitude > 90 {
+		return geoPoint{}, fmt.Errorf("invalid latitude %q, want number from -90 to 90", lat)
+	}
+
+	longitude, err := strconv.ParseFloat(strings.TrimSpace(lon), 64)
+	if err != nil || longitude < -180 || longitude > 180 {
+		return geoPoint{}, fmt.Errorf("invalid longitude %q, want number from -180 to 180", lon)
+	}
+
+	return geoPoint{Lat: latitude, Lon: longitude}, nil
+}
+
+// ----------------------------------------------------------------------------
+// parse geo URI: "geo:55.75,37.61", "geo:55.75,37.61,150;u=30", altitude and parameters are ignored
+func parseGeoURI(uri string) (geoPoint, error) {
+	if !strings.HasPrefix(strings.ToLower(uri), geoURIScheme) {
+		return geoPoint{}, fmt.Errorf("invalid geo URI %q", uri)
+	}
+
+	coords := uri[len(geoURIScheme):]
+	if i := strings.IndexAny(coords, ";?"); i >= 0 {
+		coords = coords[:i]
+	}
+	parts := strings.Split(coords, ",")
+	if len(parts) < 2 || len(parts) > 3 {
+		return geoPoint{}, fmt.Errorf("invalid geo URI %q, want geo:lat,lon", uri)
+	}
+
+	return parseGeoPoint(parts[0], parts[1])
+}
+
+// ----------------------------------------------------------------------------
+// location from config: geo URI in city or -lat/-lon options,
+// the one from source with higher precedence wins, nil for city
+func parseLocation(cfg config) (*geoPoint, error) {
+	if strings.HasPrefix(strings.ToLower(cfg.city), geoURIScheme) {
+		point, err := parseGeoURI(cfg.city)
+		return &point, err
+	}
+
+	if cfg.lat == "" && cfg.lon == "" {
+		return nil, nil
+	}
+	if cfg.lat == "" || cfg.lon == "" {
+		return nil, fmt.Errorf("both -lat and -lon are required")
+	}
+	if cfg.city != "" && sourceRank(cfg.sources[configKeyCity]) > sourceRank(cfg.sources["lat"]) {
+		return nil, nil
+	}
+
+	point, err := parseGeoPoint(cfg.lat, cfg.lon)
+	return &point, err
+}
+
+// ----------------------------------------------------------------------------
+// precedence of source of setting
+func sourceRank(source string) int {
+	for i, src := range []string{sourceDefault, sourceConfig, sourceProfile, sourceEnv, sourceFlag} {
+		if src == source {
+			return i
+		}
+	}
+	return 0
+}
+
+// ----------------------------------------------------------------------------
+// coordinates for URL and header: "55.7558,37.6173"
+func (point geoPoint) String() string {
+	return strconv.FormatFloat(point.Lat, 'f', -1, 64) + "," + strconv.FormatFloat(point.Lon, 'f', -1, 64)
+}
+
+// ----------------------------------------------------------------------------
+// URL of yandex page for city or coordinates
+func (cfg config) pageURL(baseURL string) string {
+	if cfg.location != nil {
+		return fmt.Sprintf("%s?lat=%s&lon=%s",
+			baseURL, strconv.FormatFloat(cfg.location.Lat, 'f', -1, 64), strconv.FormatFloat(cfg.location.Lon, 'f', -1, 64))
+	}
+
+	return baseURL + cfg.city
+}
+
+// ----------------------------------------------------------------------------
+// name of place from title of page, title as is if it has unknown format
+func placeFromTitle(title string) string {
+	if match := reTitlePlace.FindStringSubmatch(strings.TrimSpace(title)); match != nil {
+		return match[1]
+	}
+	return title
+}
+
+// ----------------------------------------------------------------------------
+// coordinates of location or of city from gazetteer, false for unknown city
+func (cfg config) coordinates() (geoPoint, bool) {
+	if cfg.location != nil {
+		return *cfg.location, true
+	}
+
+	city, ok := lookupCity(cfg.city)
+	if !ok {
+		return geoPoint{}, false
+	}
+	return geoPoint{Lat: city.Lat, Lon: city.Lon}, true
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"testing"
+)
+
+func Test_parseGeoURI(t *testing.T) {
+	tests := []struct {
+		uri     string
+		want    geoPoint
+		wantErr bool
+	}{
+		{"geo:55.915,36.86", geoPoint{55.915, 36.86}, false},
+		{"GEO:-33.8688,151.2093", geoPoint{-33.8688, 151.2093}, false},
+		{"geo:40.7128,-74.006,10", geoPoint{40.7128, -74.006}, false},
+		{"geo:55.915,36.86;u=35", geoPoint{55.915, 36.86}, false},
+		{"geo:55.915,36.86?z=10", geoPoint{55.915, 36.86}, false},
+		{"geo:55.915", geoPoint{}, true},
+		{"geo:1,2,3,4", geoPoint{}, true},
+		{"geo:91,0", geoPoint{}, true},
+		{"geo:0,181", geoPoint{}, true},
+		{"geo:north,east", geoPoint{}, true},
+		{"moscow", geoPoint{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseGeoURI(tt.uri)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseGeoURI(%q) = %v, %v, want %v, error: %v", tt.uri, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func Test_parseLocation(t *testing.T) {
+	tests := []struct {
+		name     string
+		city     string
+		lat, lon string
+		sources  map[string]string
+		want     string
+		wantErr  bool
+	}{
+		{name: "city", city: "moscow", want: ""},
+		{name: "geo URI", city: "geo:55.915,36.86", want: "55.915,36.86"},
+		{name: "lat and lon", lat: "55.915", lon: "36.86", want: "55.915,36.86"},
+		{name: "only lat", lat: "55.915", wantErr: true},
+		{name: "invalid lon", lat: "55.915", lon: "east", wantErr: true},
+		{
+			name: "city from flag, coordinates from profile", city: "kyiv", lat: "55.915", lon: "36.86",
+			sources: map[string]string{"city": sourceFlag, "lat": sourceProfile, "lon": sourceProfile},
+			want:    "",
+		},
+		{
+			name: "coordinates from flag, city from profile", city: "kyiv", lat: "55.915", lon: "36.86",
+			sources: map[string]string{"city": sourceProfile, "lat": sourceFlag, "lon": sourceFlag},
+			want:    "55.915,36.86",
+		},
+	}
+
+	for _, tt := range tests {
+		cfg := defaultConfig()
+		cfg.city, cfg.lat, cfg.lon, cfg.sources = tt.city, tt.lat, tt.lon, tt.sources
+		location, err := parseLocation(cfg)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%q. parseLocation() error = %v, want error: %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		got := ""
+		if location != nil && err == nil {
+			got = location.String()
+		}
+		if got != tt.want {
+			t.Errorf("%q. parseLocation() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func Test_pageURL(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.city = "kyiv"
+	if got := cfg.pageURL(cfg.baseURL); got != "https://yandex.ru/pogoda/kyiv" {
+		t.Errorf("pageURL() for city = %q", got)
+	}
+
+	cfg.location = &geoPoint{Lat: 55.915, Lon: -36.86}
+	if got := cfg.pageURL(cfg.baseURL); got != "https://yandex.ru/pogoda/?lat=55.915&lon=-36.86" {
+		t.Errorf("pageURL() for coordinates = %q", got)
+	}
+	if got := cfg.pageURL(cfg.baseURLMini); got != "https://p.ya.ru/?lat=55.915&lon=-36.86" {
+		t.Errorf("pageURL() of mini page for coordinates = %q", got)
+	}
+}
+
+func Test_placeFromTitle(t *testing.T) {
+	tests := []struct {
+		title, want string
+	}{
+		{"Погода в Истре на 10 дней — Яндекс Погода", "Истре"},
+		{"Погода в Ростове-на-Дону — Яндекс Погода", "Ростове-на-Дону"},
+		{"Погода во Владивостоке сегодня", "Владивостоке"},
+		{"Погода в посёлке Снегири", "посёлке Снегири"},
+		{"Weather in Istra for 10 days | Yandex", "Istra"},
+		{"Истра", "Истра"},
+	}
+
+	for _, tt := range tests {
+		if got := placeFromTitle(tt.title); got != tt.want {
+			t.Errorf("placeFromTitle(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// one line output by template for shell prompts and status bars
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// onelineTemplateDefault - default template of -oneline output
+	onelineTemplateDefault = "{icon} {temp}° {desc}"
+	// onelineCacheTTL - cache TTL for -oneline and bars if -cache-ttl is not set, they are rendered often
+	onelineCacheTTL = 10 * time.Minute
+	// onelineDeadline - network deadline for -oneline and bars if -deadline is not set
+	onelineDeadline = 2 * time.Second
+)
+
+// conditions - weather conditions by description, checked in order
+var conditions = []struct {
+	name string
+	re   *regexp.Regexp
+}{
+	{"thunder", regexp.MustCompile(`(?i)гроз|thunder`)},
+	{"snow", rePrecipDesc},
+	{"rain", reRainyDesc},
+	{"fog", regexp.MustCompile(`(?i)туман|дымка|мгла|fog|mist|haze`)},
+	{"partly-cloudy", regexp.MustCompile(`(?i)прояснени|малооблачно|переменная облачность|partly|mostly clear`)},
+	{"cloudy", regexp.MustCompile(`(?i)облачно|пасмурно|cloud|overcast`)},
+	{"clear", regexp.MustCompile(`(?i)ясно|clear|sunny`)},
+}
+
+// conditionIcons - unicode symbols for weather conditions
+var conditionIcons = map[string]string{
+	"thunder":       "⚡",
+	"snow":          "✻",
+	"rain":          "☂",
+	"fog":           "≡",
+	"partly-cloudy": "⛅",
+	"cloudy":        "☁",
+	"clear":         "☀",
+}
+
+// ----------------------------------------------------------------------------
+// weather condition by description, empty for unknown
+func weatherCondition(desc string) string {
+	for _, condition := range conditions {
+		if condition.re.MatchString(desc) {
+			return condition.name
+		}
+	}
+	return ""
+}
+
+// ----------------------------------------------------------------------------
+// cached page is used and network is limited by deadline, if these options are not set
+func setupCache(cfg *config) {
+	if _, ok := cfg.sources[configKey("cache-ttl")]; !ok {
+		cfg.cacheTTL = onelineCacheTTL
+	}
+	if _, ok := cfg.sources["deadline"]; !ok {
+		cfg.deadline = onelineDeadline
+	}
+}
+
+// ----------------------------------------------------------------------------
+// only current weather from main page is needed for -oneline
+func setupOneline(cfg *config) {
+	setupCache(cfg)
+	cfg.noToday, cfg.detailed, cfg.hoursDays = true, false, 0
+}
+
+// ----------------------------------------------------------------------------
+// current weather by template: "☁ 12° Облачно", unknown placeholders are left as is
+func renderOneline(forecastNow map[string]interface{}, cfg config) string {
+	desc, _ := forecastNow["desc_now"].(string)
+	title, _ := forecastNow["city"].(string)
+	wind, _ := forecastNow["wind"].(string)
+	humidity, _ := forecastNow["humidity"].(string)
+	pressure, _ := forecastNow["pressure"].(string)
+
+	temp := ""
+	if termNow, ok := forecastNow["term_now"].(int); ok {
+		temp = strconv.Itoa(termNow)
+	}
+	warning := ""
+	if warnings, _ := forecastNow["warnings"].([]weatherWarning); len(warnings) > 0 {
+		warning = "⚠"
+	}
+
+	replacer := strings.NewReplacer(
+		"{icon}", conditionIcons[weatherCondition(desc)],
+		"{temp}", temp,
+		"{units}", cfg.tempUnit(),
+		"{desc}", desc,
+		"{city}", cfg.cityName(title),
+		"{wind}", wind,
+		"{humidity}", humidity,
+		"{pressure}", pressure,
+		"{warning}", warning,
+	)
+
+	return strings.TrimSpace(replacer.Replace(cfg.template))
+}
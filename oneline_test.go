@@ -0,0 +1,86 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_weatherCondition(t *testing.T) {
+	tests := []struct {
+		desc string
+		want string
+	}{
+		{desc: "Облачно с прояснениями", want: "partly-cloudy"},
+		{desc: "Малооблачно", want: "partly-cloudy"},
+		{desc: "Пасмурно", want: "cloudy"},
+		{desc: "Небольшой дождь", want: "rain"},
+		{desc: "Дождь с грозой", want: "thunder"},
+		{desc: "Снег с дождём", want: "snow"},
+		{desc: "Туман", want: "fog"},
+		{desc: "Ясно", want: "clear"},
+		{desc: "Partly cloudy", want: "partly-cloudy"},
+		{desc: "Light rain", want: "rain"},
+		{desc: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := weatherCondition(tt.desc); got != tt.want {
+			t.Errorf("weatherCondition(%q) = %q, want %q", tt.desc, got, tt.want)
+		}
+	}
+}
+
+func Test_renderOneline(t *testing.T) {
+	forecastNow := map[string]interface{}{
+		"city":     "Погода в Москве на 10 дней — Яндекс Погода",
+		"term_now": 12,
+		"desc_now": "Облачно",
+		"wind":     "3 м/с, СЗ",
+		"humidity": "71%",
+		"pressure": "745 мм рт. ст.",
+		"warnings": []weatherWarning{{Severity: severityYellow, Text: "Сильный ветер"}},
+	}
+
+	tests := []struct {
+		template string
+		units    string
+		want     string
+	}{
+		{template: onelineTemplateDefault, want: "☁ 12° Облачно"},
+		{template: "{temp}{units} {humidity} {pressure}", units: unitsFahrenheit, want: "12°F 71% 745 мм рт. ст."},
+		{template: "{warning} {city}: {wind}", want: "⚠ Москва: 3 м/с, СЗ"},
+		{template: "{icon} {unknown}", want: "☁ {unknown}"},
+	}
+
+	for _, tt := range tests {
+		cfg := defaultConfig()
+		cfg.city, cfg.template = "moscow", tt.template
+		if tt.units != "" {
+			cfg.units = tt.units
+		}
+		if got := renderOneline(forecastNow, cfg); got != tt.want {
+			t.Errorf("renderOneline(%q) = %q, want %q", tt.template, got, tt.want)
+		}
+	}
+
+	if got := renderOneline(map[string]interface{}{"desc_now": "Unknown"}, defaultConfig()); got != "° Unknown" {
+		t.Errorf("renderOneline() without temperature and icon = %q", got)
+	}
+}
+
+func Test_setupOneline(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.detailed, cfg.hoursDays = true, 3
+	setupOneline(&cfg)
+	if cfg.cacheTTL != onelineCacheTTL || cfg.deadline != onelineDeadline || !cfg.noToday || cfg.detailed || cfg.hoursDays != 0 {
+		t.Errorf("setupOneline() = %+v", cfg)
+	}
+
+	cfg = defaultConfig()
+	cfg.cacheTTL, cfg.deadline = 0, 5*time.Second
+	cfg.sources = map[string]string{"cache_ttl": sourceFlag, "deadline": sourceConfig}
+	setupOneline(&cfg)
+	if cfg.cacheTTL != 0 || cfg.deadline != 5*time.Second {
+		t.Errorf("setupOneline() with options = %v, %v", cfg.cacheTTL, cfg.deadline)
+	}
+}
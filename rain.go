@@ -0,0 +1,237 @@
+// "rain" command: when does the rain start or stop
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// exitCodeNoRain - exit code of "rain" command if there is no rain in the next hours
+	exitCodeNoRain = 4
+	// rainProbMin - minimal probability of precipitation for rainy hour, %
+	rainProbMin = 50
+	// rainTimeLayout - layout of start and end of rain in JSON
+	rainTimeLayout = "2006-01-02T15:04"
+)
+
+// reRainyDesc - description of day with rain
+var reRainyDesc = regexp.MustCompile(`(?i)дожд|ливн|ливень|гроз|морос|rain|shower|drizzle|thunder`)
+
+// rainAnswer - answer of "rain" command
+type rainAnswer struct {
+	Text        string `json:"text"`
+	RainNow     bool   `json:"rain_now"`
+	Start       string `json:"start,omitempty"`         // start of rain in the next hours: "2006-01-02T15:04"
+	End         string `json:"end,omitempty"`           // first hour without rain after start
+	NextRainDay string `json:"next_rain_day,omitempty"` // first day with rain after today: "2006-01-02"
+}
+
+// rainHour - hour of forecast by hours with date
+type rainHour struct {
+	time time.Time
+	day  int // days from today
+	rain bool
+}
+
+// ----------------------------------------------------------------------------
+// rain in hour of forecast: by icon, amount or probability of precipitation
+func isRainyHour(item hourTemp) bool {
+	return strings.Contains(item.Icon, "rain") ||
+		item.Precip != nil && *item.Precip > 0 ||
+		item.PrecipProb != nil && *item.PrecipProb >= rainProbMin
+}
+
+// ----------------------------------------------------------------------------
+// rain in day forecast by description
+func isRainyDay(day dayForecast) bool {
+	return reRainyDesc.MatchString(day.Desc)
+}
+
+// ----------------------------------------------------------------------------
+// hours with dates, hours without date are from today and go to next day after midnight,
+// hours without date are from tomorrow if the first hour is before current hour: 00:00 at 23:30
+func rainHours(forecastByHours []hourTemp, cfg config) []rainHour {
+	today, err := cfg.parseDate(cfg.today())
+	if err != nil {
+		return nil
+	}
+
+	result := []rainHour{}
+	day := 0
+	if len(forecastByHours) > 0 && forecastByHours[0].Date == "" && forecastByHours[0].Hour < cfg.now().Hour() {
+		day = 1
+	}
+	for i, item := range forecastByHours {
+		switch {
+		case item.Date != "":
+			if date, err := cfg.parseDate(item.Date); err == nil {
+				day = int(date.Sub(today).Hours()/24 + 0.5)
+			}
+		case i > 0 && item.Hour < forecastByHours[i-1].Hour:
+			day++
+		}
+
+		date := today.AddDate(0, 0, day)
+		result = append(result, rainHour{
+			time: time.Date(date.Year(), date.Month(), date.Day(), item.Hour, 0, 0, 0, date.Location()),
+			day:  day,
+			rain: isRainyHour(item),
+		})
+	}
+
+	return result
+}
+
+// ----------------------------------------------------------------------------
+// answer "when will it rain" from forecast by hours and by days
+func rainForecast(forecastByHours []hourTemp, forecastNext []dayForecast, cfg config) rainAnswer {
+	hours := rainHours(forecastByHours, cfg)
+
+	start, end := -1, -1
+	for i, hour := range hours {
+		if start == -1 && hour.rain {
+			start = i
+		}
+		if start != -1 && !hour.rain {
+			end = i
+			break
+		}
+	}
+
+	answer := rainAnswer{RainNow: start == 0 && len(hours) > 0}
+	if start != -1 {
+		answer.Start = hours[start].time.Format(rainTimeLayout)
+	}
+	if end != -1 {
+		answer.End = hours[end].time.Format(rainTimeLayout)
+	}
+
+	hoursText := ""
+	switch {
+	case len(hours) == 0:
+	case answer.RainNow && end != -1:
+		hoursText = cfg.tr("rain_now") + " " + cfg.rainHoursText("rain_until", hours, end)
+	case answer.RainNow:
+		hoursText = cfg.tr("rain_now") + ", " + fmt.Sprintf(cfg.tr("rain_hours"), len(hours))
+	case start != -1 && end != -1:
+		hoursText = cfg.rainHoursText("rain_from_to", hours, start, end)
+	case start != -1:
+		hoursText = cfg.rainHoursText("rain_from", hours, start)
+	default:
+		hoursText = fmt.Sprintf(cfg.tr("no_rain_hours"), len(hours))
+	}
+
+	daysText := ""
+	today := cfg.today()
+	days := []dayForecast{}
+	for _, day := range forecastNext {
+		if day.Date > today {
+			days = append(days, day)
+		}
+	}
+	for _, day := range days {
+		if isRainyDay(day) {
+			answer.NextRainDay = day.Date
+			break
+		}
+	}
+	switch {
+	case answer.NextRainDay != "":
+		daysText = cfg.rainDayText(answer.NextRainDay)
+	case len(days) > 0:
+		daysText = fmt.Sprintf(cfg.tr("dry_forecast"), days[len(days)-1].DateHuman)
+	}
+
+	answer.Text = strings.Join(nonEmpty(hoursText, daysText), ", "+cfg.tr("then")+" ")
+	return answer
+}
+
+// ----------------------------------------------------------------------------
+// message with times of hours: "rain from 15:00 to 19:00 today",
+// or "rain from 22:00 today to 02:00 tomorrow" if hours are in different days
+func (cfg config) rainHoursText(key string, hours []rainHour, indexes ...int) string {
+	labels := []interface{}{}
+	sameDay := true
+	for _, i := range indexes {
+		labels = append(labels, hours[i].time.Format("15:04"))
+		sameDay = sameDay && hours[i].day == hours[indexes[0]].day
+	}
+
+	if sameDay {
+		return fmt.Sprintf(cfg.tr(key), labels...) + " " + cfg.dayWord(hours[indexes[0]].day)
+	}
+	for j, i := range indexes {
+		labels[j] = fmt.Sprintf("%s %s", labels[j], cfg.dayWord(hours[i].day))
+	}
+	return fmt.Sprintf(cfg.tr(key), labels...)
+}
+
+// ----------------------------------------------------------------------------
+// "today", "tomorrow" or date for days from today
+func (cfg config) dayWord(days int) string {
+	switch days {
+	case 0:
+		return cfg.tr("today")
+	case 1:
+		return cfg.tr("tomorrow")
+	}
+
+	day, err := cfg.parseDate(cfg.today())
+	if err != nil {
+		return ""
+	}
+	dateHuman, _ := formatDates(day.AddDate(0, 0, days), cfg.lang)
+	return dateHuman
+}
+
+// ----------------------------------------------------------------------------
+// part of message about next day with rain: "rain tomorrow", "dry until Thursday"
+func (cfg config) rainDayText(date string) string {
+	day, err := cfg.parseDate(date)
+	if err != nil {
+		return ""
+	}
+	today, err := cfg.parseDate(cfg.today())
+	if err != nil {
+		return ""
+	}
+
+	switch days := int(day.Sub(today).Hours()/24 + 0.5); {
+	case days == 1:
+		return cfg.tr("rain_tomorrow")
+	case days < 7:
+		names, ok := weekdaysUntil[cfg.lang]
+		if !ok {
+			names = weekdaysUntil[langRu]
+		}
+		return fmt.Sprintf(cfg.tr("dry_until"), names[day.Weekday()])
+	default:
+		dateHuman, _ := formatDates(day, cfg.lang)
+		return fmt.Sprintf(cfg.tr("dry_until"), dateHuman)
+	}
+}
+
+// ----------------------------------------------------------------------------
+// "rain" command
+func runRain(cfg config, _ []string) int {
+	cfg.noToday, cfg.hoursDays = false, 0
+	forecastNow, forecastByHours, forecastNext := getWeather(&cfg)
+	exitIfCityNotFound(forecastNow, cfg)
+
+	answer := rainForecast(forecastByHours, forecastNext, cfg)
+	if cfg.getJSON {
+		jsonBytes, _ := json.Marshal(answer)
+		fmt.Println(string(jsonBytes))
+	} else {
+		fmt.Println(answer.Text)
+	}
+
+	if answer.Start == "" {
+		return exitCodeNoRain
+	}
+	return 0
+}
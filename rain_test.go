@@ -0,0 +1,98 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_rainForecast(t *testing.T) {
+	prob := func(value int) *int { return &value }
+	days := []dayForecast{
+		{Date: "2021-10-22", DateHuman: "22.10 (пт)", Desc: "дождь"},
+		{Date: "2021-10-23", DateHuman: "23.10 (сб)", Desc: "облачно"},
+		{Date: "2021-10-24", DateHuman: "24.10 (вс)", Desc: "ясно"},
+		{Date: "2021-10-25", DateHuman: "25.10 (пн)", Desc: "небольшой дождь"},
+	}
+
+	tests := []struct {
+		name  string
+		lang  string
+		clock time.Time // 2021-10-22 14:00 by default
+		hours []hourTemp
+		days  []dayForecast
+		want  rainAnswer
+	}{
+		{
+			name:  "rain later today",
+			lang:  langEn,
+			hours: []hourTemp{{Hour: 14}, {Hour: 15, Icon: "icon_rain"}, {Hour: 16, PrecipProb: prob(80)}, {Hour: 17}, {Hour: 18}},
+			days:  days,
+			want: rainAnswer{
+				Text:  "rain from 15:00 to 17:00 today, then dry until Monday",
+				Start: "2021-10-22T15:00", End: "2021-10-22T17:00", NextRainDay: "2021-10-25",
+			},
+		},
+		{
+			name:  "rain now until tomorrow",
+			lang:  langRu,
+			hours: []hourTemp{{Hour: 22, Icon: "icon_rain"}, {Hour: 23, Icon: "icon_rain"}, {Hour: 0, Icon: "icon_rain"}, {Hour: 1}},
+			days:  days[:3],
+			want: rainAnswer{
+				Text:    "дождь идёт сейчас до 01:00 завтра, затем без дождя в прогнозе до 24.10 (вс)",
+				RainNow: true, Start: "2021-10-22T22:00", End: "2021-10-23T01:00",
+			},
+		},
+		{
+			name:  "rain from evening to night",
+			lang:  langEn,
+			hours: []hourTemp{{Hour: 22}, {Hour: 23, Icon: "icon_rain"}, {Hour: 0, Icon: "icon_rain"}, {Hour: 1}},
+			want: rainAnswer{
+				Text:  "rain from 23:00 today to 01:00 tomorrow",
+				Start: "2021-10-22T23:00", End: "2021-10-23T01:00",
+			},
+		},
+		{
+			name:  "dry hours, rain tomorrow",
+			lang:  langEn,
+			hours: []hourTemp{{Hour: 14, PrecipProb: prob(20)}, {Hour: 15}},
+			days:  []dayForecast{{Date: "2021-10-23", Desc: "Rain"}},
+			want:  rainAnswer{Text: "no rain in next 2 hours, then rain tomorrow", NextRainDay: "2021-10-23"},
+		},
+		{
+			name:  "near midnight, hours are from tomorrow",
+			lang:  langEn,
+			clock: time.Date(2021, 10, 22, 23, 30, 0, 0, time.FixedZone("MSK", 3*3600)),
+			hours: []hourTemp{{Hour: 0}, {Hour: 1, Icon: "icon_rain"}, {Hour: 2}, {Hour: 3}},
+			want: rainAnswer{
+				Text:  "rain from 01:00 to 02:00 tomorrow",
+				Start: "2021-10-23T01:00", End: "2021-10-23T02:00",
+			},
+		},
+		{
+			name:  "near midnight, current hour is today",
+			lang:  langEn,
+			clock: time.Date(2021, 10, 22, 23, 30, 0, 0, time.FixedZone("MSK", 3*3600)),
+			hours: []hourTemp{{Hour: 23, Icon: "icon_rain"}, {Hour: 0}},
+			want: rainAnswer{
+				Text:    "rain now until 00:00 tomorrow",
+				RainNow: true, Start: "2021-10-22T23:00", End: "2021-10-23T00:00",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		cfg := defaultConfig()
+		cfg.lang = tt.lang
+		cfg.timeZone = time.FixedZone("MSK", 3*3600)
+		clock := tt.clock
+		if clock.IsZero() {
+			clock = time.Date(2021, 10, 22, 14, 0, 0, 0, cfg.timeZone)
+		}
+		cfg.clock = func() time.Time { return clock }
+
+		if got := rainForecast(tt.hours, tt.days, cfg); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q. rainForecast() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,308 @@
+// config file with defaults and named profiles, merging of settings from all sources
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// configFileName - config file in user config directory
+	configFileName = "config.toml"
+	// configProfilePrefix - prefix of sections with profiles: [profile.home]
+	configProfilePrefix = "profile."
+	// configThemePrefix - prefix of sections with custom themes: [theme.my]
+	configThemePrefix = "theme."
+	// configKeyGradient - key for gradient of theme, other keys of theme section are roles
+	configKeyGradient = "gradient"
+	// configKeyProfile - key for profile by default in config file
+	configKeyProfile = "profile"
+	// configKeyCity - key for city, it is not a flag
+	configKeyCity = "city"
+	// configKeyFavorites - key for list of favorite cities, for completion
+	configKeyFavorites = "favorites"
+)
+
+// sources of settings, from low to high precedence
+const (
+	sourceDefault = "default"
+	sourceConfig  = "config"
+	sourceProfile = "profile"
+	sourceEnv     = "env"
+	sourceFlag    = "flag"
+)
+
+// notConfigurableFlags - flags which can be set only in command line
+var notConfigurableFlags = map[string]bool{
+	"version":      true,
+	"config":       true,
+	"profile":      true,
+	"json":         true,
+	"summary":      true,
+	"accessible":   true,
+	"oneline":      true,
+	"no-color":     true,
+	"as-of":        true,
+	"page":         true,
+	"page-mini":    true,
+	"page-details": true,
+}
+
+// configFile - parsed config file, sections by name, "" for top level settings
+type configFile map[string]map[string]string
+
+// settingsLayer - settings from one source
+type settingsLayer struct {
+	source   string
+	settings map[string]string
+}
+
+// ----------------------------------------------------------------------------
+// path of config file in user config directory ($XDG_CONFIG_HOME on Linux)
+func defaultConfigPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(configDir, appConfigDirName, configFileName)
+}
+
+// ----------------------------------------------------------------------------
+// read config file, missing file is not an error
+func readConfigFile(path string) (configFile, error) {
+	if path == "" {
+		return configFile{}, nil
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return configFile{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	result, err := parseConfigFile(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+
+	return result, nil
+}
+
+// ----------------------------------------------------------------------------
+// parse config file in TOML subset: comments, [sections], key = "string", numbers, booleans and arrays
+func parseConfigFile(reader io.Reader) (configFile, error) {
+	result := configFile{"": {}}
+	section := ""
+	scanner := bufio.NewScanner(reader)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(stripConfigComment(scanner.Text()))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			if section == "" {
+				return nil, fmt.Errorf("line %d: empty section name", lineNum)
+			}
+			if _, exists := result[section]; !exists {
+				result[section] = map[string]string{}
+			}
+			continue
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("line %d: want key = value", lineNum)
+		}
+
+		value, err := parseConfigValue(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", lineNum, err)
+		}
+		result[section][strings.TrimSpace(parts[0])] = value
+	}
+
+	return result, scanner.Err()
+}
+
+// ----------------------------------------------------------------------------
+// remove comment from line, "#" inside strings is not a comment
+func stripConfigComment(line string) string {
+	var quote rune
+	for i, char := range line {
+		switch {
+		case quote != 0 && char == quote:
+			quote = 0
+		case quote == 0 && (char == '"' || char == '\''):
+			quote = char
+		case quote == 0 && char == '#':
+			return line[:i]
+		}
+	}
+
+	return line
+}
+
+// ----------------------------------------------------------------------------
+// unquote string value, other values as is
+func parseConfigValue(value string) (string, error) {
+	switch {
+	case value == "":
+		return "", fmt.Errorf("empty value")
+	case value[0] == '"':
+		return strconv.Unquote(value)
+	case value[0] == '\'':
+		if len(value) < 2 || value[len(value)-1] != '\'' {
+			return "", fmt.Errorf("unterminated string: %s", value)
+		}
+		return value[1 : len(value)-1], nil
+	}
+
+	return value, nil
+}
+
+// ----------------------------------------------------------------------------
+// parse array value from config: ["a", "b"]
+func parseConfigList(value string) ([]string, error) {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, "[") || !strings.HasSuffix(value, "]") {
+		return nil, fmt.Errorf("want array: %s", value)
+	}
+
+	result := []string{}
+	for _, item := range strings.Split(value[1:len(value)-1], ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		item, err := parseConfigValue(item)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+
+	return result, nil
+}
+
+// ----------------------------------------------------------------------------
+// names of profiles from config file
+func (cf configFile) profiles() []string {
+	result := []string{}
+	for section := range cf {
+		if strings.HasPrefix(section, configProfilePrefix) {
+			result = append(result, strings.TrimPrefix(section, configProfilePrefix))
+		}
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// ----------------------------------------------------------------------------
+// settings of profile, error for unknown profile
+func (cf configFile) profile(name string) (map[string]string, error) {
+	if name == "" {
+		return map[string]string{}, nil
+	}
+
+	settings, ok := cf[configProfilePrefix+name]
+	if !ok {
+		return nil, fmt.Errorf("profile %q not found in config, available: %s", name, strings.Join(cf.profiles(), ", "))
+	}
+
+	return settings, nil
+}
+
+// ----------------------------------------------------------------------------
+// key in config file for flag name: "no-today" -> "no_today"
+func configKey(flagName string) string {
+	return strings.Replace(flagName, "-", "_", -1)
+}
+
+// ----------------------------------------------------------------------------
+// set one setting to config via flags of flag set
+func applySetting(fs *flag.FlagSet, cfg *config, source, key, value string) error {
+	if key == configKeyCity {
+		cfg.city = value
+		return nil
+	}
+
+	flagName := strings.Replace(key, "_", "-", -1)
+	if fs.Lookup(flagName) == nil || notConfigurableFlags[flagName] && source != sourceFlag {
+		return fmt.Errorf("unknown setting %q", key)
+	}
+
+	if err := fs.Set(flagName, value); err != nil {
+		return fmt.Errorf("invalid value for %q: %s", key, err)
+	}
+
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// apply settings layers in order of precedence, returns source of each applied setting
+func mergeSettings(fs *flag.FlagSet, cfg *config, layers []settingsLayer) (map[string]string, error) {
+	sources := map[string]string{}
+	for _, layer := range layers {
+		keys := make([]string, 0, len(layer.settings))
+		for key := range layer.settings {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if err := applySetting(fs, cfg, layer.source, key, layer.settings[key]); err != nil {
+				return nil, fmt.Errorf("%s: %s", layer.source, err)
+			}
+			sources[key] = layer.source
+		}
+	}
+
+	return sources, nil
+}
+
+// ----------------------------------------------------------------------------
+// format effective config as config file with sources of values
+func formatConfig(cfg config) string {
+	fs := newFlagSet(&cfg)
+	source := func(key string) string {
+		if src, ok := cfg.sources[key]; ok {
+			return src
+		}
+		return sourceDefault
+	}
+
+	result := strings.Builder{}
+	if cfg.profile != "" {
+		result.WriteString(fmt.Sprintf("# profile: %s\n", cfg.profile))
+	}
+	result.WriteString(fmt.Sprintf("%s = %q # %s\n", configKeyCity, cfg.city, source(configKeyCity)))
+
+	fs.VisitAll(func(f *flag.Flag) {
+		if notConfigurableFlags[f.Name] {
+			return
+		}
+
+		value := f.Value.String()
+		if !isBoolFlag(f) {
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				value = strconv.Quote(value)
+			}
+		}
+		result.WriteString(fmt.Sprintf("%s = %s # %s\n", configKey(f.Name), value, source(configKey(f.Name))))
+	})
+
+	return result.String()
+}
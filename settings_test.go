@@ -0,0 +1,135 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_parseConfigFile(t *testing.T) {
+	in := `# defaults
+city = "moscow"  # comment
+days = 7
+color = 'always'
+favorites = ["kyiv", "london # not comment"]
+
+[profile.dacha]
+city = "istra"
+cache_ttl = "10m"
+
+[profile.office]
+`
+	want := configFile{
+		"": {
+			"city":      "moscow",
+			"days":      "7",
+			"color":     "always",
+			"favorites": `["kyiv", "london # not comment"]`,
+		},
+		"profile.dacha": {
+			"city":      "istra",
+			"cache_ttl": "10m",
+		},
+		"profile.office": {},
+	}
+
+	got, err := parseConfigFile(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("parseConfigFile() error: %s", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseConfigFile() = %v, want %v", got, want)
+	}
+	if profiles := got.profiles(); !reflect.DeepEqual(profiles, []string{"dacha", "office"}) {
+		t.Errorf("profiles() = %v", profiles)
+	}
+
+	list, err := parseConfigList(got[""]["favorites"])
+	if err != nil || !reflect.DeepEqual(list, []string{"kyiv", "london # not comment"}) {
+		t.Errorf("parseConfigList() = %v, %v", list, err)
+	}
+
+	for _, in := range []string{"key", "key = ", `key = "unterminated`, "[]", "= value", "key = 'str"} {
+		if _, err := parseConfigFile(strings.NewReader(in)); err == nil {
+			t.Errorf("parseConfigFile(%q) want error", in)
+		}
+	}
+}
+
+func Test_mergeSettings(t *testing.T) {
+	cfg := defaultConfig()
+	fs := newFlagSet(&cfg)
+
+	sources, err := mergeSettings(fs, &cfg, []settingsLayer{
+		{source: sourceConfig, settings: map[string]string{"city": "moscow", "days": "5", "units": "f", "cache_ttl": "1m"}},
+		{source: sourceProfile, settings: map[string]string{"city": "istra", "days": "3"}},
+		{source: sourceEnv, settings: map[string]string{"theme": "colorblind"}},
+		{source: sourceFlag, settings: map[string]string{"days": "2", "json": "true"}},
+	})
+	if err != nil {
+		t.Fatalf("mergeSettings() error: %s", err)
+	}
+
+	if cfg.city != "istra" || cfg.daysLimit != 2 || cfg.units != "f" || cfg.theme != "colorblind" || !cfg.getJSON || cfg.cacheTTL != time.Minute {
+		t.Errorf("mergeSettings() config = %+v", cfg)
+	}
+
+	wantSources := map[string]string{
+		"city":      sourceProfile,
+		"days":      sourceFlag,
+		"units":     sourceConfig,
+		"cache_ttl": sourceConfig,
+		"theme":     sourceEnv,
+		"json":      sourceFlag,
+	}
+	if !reflect.DeepEqual(sources, wantSources) {
+		t.Errorf("mergeSettings() sources = %v, want %v", sources, wantSources)
+	}
+
+	cfg.sources = sources
+	configText := formatConfig(cfg)
+	for _, line := range []string{
+		`city = "istra" # profile`,
+		`days = 2 # flag`,
+		`theme = "colorblind" # env`,
+		`braille = false # default`,
+		`cache_ttl = "1m0s" # config`,
+	} {
+		if !strings.Contains(configText, line+"\n") {
+			t.Errorf("formatConfig() = %s, want line %q", configText, line)
+		}
+	}
+	if strings.Contains(configText, "json") {
+		t.Errorf("formatConfig() = %s, want without command line only flags", configText)
+	}
+
+	for _, settings := range []map[string]string{{"unknown": "1"}, {"days": "many"}, {"json": "true"}, {"version": "true"}} {
+		cfg := defaultConfig()
+		if _, err := mergeSettings(newFlagSet(&cfg), &cfg, []settingsLayer{{source: sourceConfig, settings: settings}}); err == nil {
+			t.Errorf("mergeSettings(%v) want error", settings)
+		}
+	}
+}
+
+func Test_validateConfig(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.getJSON = true
+	if err := validateConfig(&cfg); err != nil || cfg.format != formatJSON {
+		t.Errorf("validateConfig() = %v, format = %q", err, cfg.format)
+	}
+
+	for _, update := range []func(*config){
+		func(cfg *config) { cfg.units = "k" },
+		func(cfg *config) { cfg.lang = "de" },
+		func(cfg *config) { cfg.format = "xml" },
+		func(cfg *config) { cfg.colorMode = "sometimes" },
+		func(cfg *config) { cfg.theme = "unknown" },
+	} {
+		cfg := defaultConfig()
+		update(&cfg)
+		if err := validateConfig(&cfg); err == nil {
+			t.Errorf("validateConfig(%+v) want error", cfg)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+// natural-language summary of forecast: current weather, trend by hours and coming days
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const (
+	// summaryTempDelta - minimal change of temperature by hours for trend in summary
+	summaryTempDelta = 2
+	// summaryDaysMax - coming days in summary, weekday names are unambiguous within a week
+	summaryDaysMax = 6
+)
+
+// weekdaysOn - names of weekdays for "rain on Tuesday" by language, from Sunday
+var weekdaysOn = map[string][7]string{
+	langRu: {"в воскресенье", "в понедельник", "во вторник", "в среду", "в четверг", "в пятницу", "в субботу"},
+	langEn: {"on Sunday", "on Monday", "on Tuesday", "on Wednesday", "on Thursday", "on Friday", "on Saturday"},
+}
+
+// ----------------------------------------------------------------------------
+// few sentences about forecast: "Now +3 °C and cloudy; warming to +7 by 15:00; rain on Tuesday."
+func summarize(forecastNow map[string]interface{}, forecastByHours []hourTemp, forecastNext []dayForecast, cfg config) string {
+	parts := []string{}
+
+	termNow, hasNow := forecastNow["term_now"].(int)
+	if hasNow && (cfg.view == "" || cfg.view == viewNow) {
+		temp := signedTemp(termNow) + " " + cfg.tempUnit()
+		if desc, _ := forecastNow["desc_now"].(string); desc != "" {
+			parts = append(parts, fmt.Sprintf(cfg.tr("summary_now"), temp, strings.ToLower(desc)))
+		} else {
+			parts = append(parts, fmt.Sprintf(cfg.tr("summary_now_temp"), temp))
+		}
+	}
+
+	if !cfg.noToday && len(forecastByHours) > 0 {
+		hours := splitHoursByDay(forecastByHours)[0]
+		if !hasNow {
+			termNow = hours[0].Temp
+		}
+		parts = append(parts, cfg.summaryHours(hours, termNow)...)
+	}
+
+	parts = append(parts, cfg.summaryDays(forecastNext)...)
+	if len(parts) == 0 {
+		return ""
+	}
+
+	return capitalize(strings.Join(parts, "; ")) + "."
+}
+
+// ----------------------------------------------------------------------------
+// trend of temperature and start of rain in forecast by hours
+func (cfg config) summaryHours(hours []hourTemp, termNow int) []string {
+	maxI, minI := 0, 0
+	for i, item := range hours {
+		if item.Temp > hours[maxI].Temp {
+			maxI = i
+		}
+		if item.Temp < hours[minI].Temp {
+			minI = i
+		}
+	}
+
+	warming := hours[maxI].Temp-termNow >= summaryTempDelta
+	cooling := termNow-hours[minI].Temp >= summaryTempDelta
+	trend := func(key string, item hourTemp) string {
+		return fmt.Sprintf(cfg.tr(key), signedTemp(item.Temp), fmt.Sprintf("%02d:00", item.Hour))
+	}
+
+	result := []string{}
+	switch {
+	case warming && cooling && minI < maxI:
+		result = append(result, trend("summary_cooling", hours[minI]), trend("summary_warming", hours[maxI]))
+	case warming && cooling:
+		result = append(result, trend("summary_warming", hours[maxI]), trend("summary_cooling", hours[minI]))
+	case warming:
+		result = append(result, trend("summary_warming", hours[maxI]))
+	case cooling:
+		result = append(result, trend("summary_cooling", hours[minI]))
+	default:
+		result = append(result, cfg.tr("summary_steady"))
+	}
+
+	rainy := rainHours(hours, cfg)
+	for i, hour := range rainy {
+		if !hour.rain {
+			continue
+		}
+		if i == 0 {
+			result = append(result, cfg.tr("rain_now"))
+		} else {
+			result = append(result, cfg.rainHoursText("rain_from", rainy, i))
+		}
+		break
+	}
+
+	return result
+}
+
+// ----------------------------------------------------------------------------
+// rain, snow and frost nights (below zero) in coming days, days without night temperature are not frost
+func (cfg config) summaryDays(forecastNext []dayForecast) []string {
+	names, ok := weekdaysOn[cfg.lang]
+	if !ok {
+		names = weekdaysOn[langRu]
+	}
+
+	today := cfg.today()
+	freezing := convertTemp(0, cfg.units)
+	rain, snow, frost := []string{}, []string{}, []string{}
+	count := 0
+	for _, day := range forecastNext {
+		date, err := cfg.parseDate(day.Date)
+		if day.Date <= today || err != nil {
+			continue
+		}
+		if count++; count > summaryDaysMax {
+			break
+		}
+
+		name := names[date.Weekday()]
+		switch {
+		case rePrecipDesc.MatchString(day.Desc):
+			snow = append(snow, name)
+		case isRainyDay(day):
+			rain = append(rain, name)
+		}
+		if !day.noTempNight && day.TempNight < freezing {
+			frost = append(frost, name)
+		}
+	}
+
+	result := []string{}
+	for _, item := range []struct {
+		key   string
+		names []string
+	}{
+		{"summary_rain", rain},
+		{"summary_snow", snow},
+		{"summary_frost", frost},
+	} {
+		if len(item.names) > 0 {
+			result = append(result, fmt.Sprintf(cfg.tr(item.key), cfg.joinNames(item.names)))
+		}
+	}
+	if len(result) == 0 && count > 0 {
+		result = append(result, cfg.tr("summary_dry"))
+	}
+
+	return result
+}
+
+// ----------------------------------------------------------------------------
+// "on Monday", "on Monday and on Tuesday", "on Monday, on Tuesday and on Friday"
+func (cfg config) joinNames(names []string) string {
+	if len(names) == 1 {
+		return names[0]
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " " + cfg.tr("and") + " " + names[len(names)-1]
+}
+
+// ----------------------------------------------------------------------------
+// temperature with sign: "+3", "-1", "0"
+func signedTemp(temp int) string {
+	if temp == 0 {
+		return "0"
+	}
+	return fmt.Sprintf("%+d", temp)
+}
+
+// ----------------------------------------------------------------------------
+// first letter in upper case
+func capitalize(text string) string {
+	runes := []rune(text)
+	if len(runes) == 0 {
+		return text
+	}
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
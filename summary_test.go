@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files of summary in testdata/summary")
+
+// summaryCase - synthetic forecast for golden test of summary
+type summaryCase struct {
+	Lang     string        `json:"lang"`
+	Units    string        `json:"units"`
+	View     string        `json:"view"`
+	Now      string        `json:"now"` // "2006-01-02T15:04"
+	TermNow  *int          `json:"term_now"`
+	DescNow  string        `json:"desc_now"`
+	ByHours  []hourTemp    `json:"by_hours"`
+	NextDays []dayForecast `json:"next_days"`
+}
+
+func Test_summarizeGolden(t *testing.T) {
+	files, err := filepath.Glob("testdata/summary/*.json")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no summary cases: %v", err)
+	}
+
+	for _, file := range files {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var input summaryCase
+		if err := json.Unmarshal(content, &input); err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+
+		cfg := defaultConfig()
+		cfg.timeZone = time.UTC
+		cfg.view = input.View
+		if input.Lang != "" {
+			cfg.lang = input.Lang
+		}
+		if input.Units != "" {
+			cfg.units = input.Units
+		}
+		now, err := time.ParseInLocation("2006-01-02T15:04", input.Now, cfg.timeZone)
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		cfg.clock = func() time.Time { return now }
+
+		forecastNow := map[string]interface{}{"desc_now": input.DescNow}
+		if input.TermNow != nil {
+			forecastNow["term_now"] = *input.TermNow
+		}
+
+		got := summarize(forecastNow, input.ByHours, input.NextDays, cfg) + "\n"
+		goldenFile := strings.TrimSuffix(file, ".json") + ".golden"
+		if *updateGolden {
+			if err := ioutil.WriteFile(goldenFile, []byte(got), 0644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+
+		want, err := ioutil.ReadFile(goldenFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != string(want) {
+			t.Errorf("%s: summarize() =\n%s\nwant\n%s", file, got, want)
+		}
+	}
+}
+
+func Test_summaryDaysNoNight(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.lang, cfg.timeZone, cfg.daysLimit = langEn, time.UTC, 10
+	cfg.clock = func() time.Time { return time.Date(2021, 10, 22, 15, 0, 0, 0, time.UTC) }
+	data := map[string][]string{
+		"date":       {"2021-10-23", "2021-10-24", "2021-10-25"},
+		"desc":       {"Clear", "Clear", "Clear"},
+		"temp":       {"5", "4", "3"},
+		"temp_night": {"", "−2", "0"},
+	}
+
+	got := cfg.summaryDays(parseNextDays(data, cfg))
+	want := []string{fmt.Sprintf(cfg.tr("summary_frost"), "on Sunday")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("summaryDays() = %q, want %q", got, want)
+	}
+}
@@ -3,10 +3,28 @@ package main
 import (
 	"fmt"
 	"io"
+	"os"
+	"runtime"
+	"strings"
 )
 
+// color modes for -color option
+const (
+	colorModeAuto   = "auto"
+	colorModeAlways = "always"
+	colorModeNever  = "never"
+)
+
+// terminalCaps - capabilities of terminal for output
+type terminalCaps struct {
+	isTerminal bool // stdout is terminal, not pipe or file
+	color      bool // colored output is enabled
+	colorDepth int  // colorDepth16, colorDepth256 or colorDepthTrue
+}
+
 type terminalWriter struct {
 	writer io.Writer
+	caps   terminalCaps
 }
 
 func (tw terminalWriter) Printf(format string, args ...interface{}) {
@@ -24,3 +42,62 @@ func (tw terminalWriter) Print(s string) {
 func (tw terminalWriter) Println(s string) {
 	tw.Print(s + "\n")
 }
+
+// ----------------------------------------------------------------------------
+// check if program's output used in *nix pipe
+func outputIsPiped() bool {
+	stdoutStat, err := os.Stdout.Stat()
+	return err != nil || (stdoutStat.Mode()&os.ModeCharDevice) == 0
+}
+
+// ----------------------------------------------------------------------------
+// detect capabilities of stdout with color mode: auto, always or never
+func detectTerminal(colorMode string) terminalCaps {
+	// cmd.exe don't detect pipe
+	isTerminal := runtime.GOOS == "windows" || !outputIsPiped()
+
+	return terminalCaps{
+		isTerminal: isTerminal,
+		color:      colorEnabled(colorMode, isTerminal, os.Getenv),
+		colorDepth: detectColorDepth(os.Getenv),
+	}
+}
+
+// ----------------------------------------------------------------------------
+// color by mode, in auto mode honor NO_COLOR (https://no-color.org)
+// and CLICOLOR/CLICOLOR_FORCE (https://bixense.com/clicolors/) conventions
+func colorEnabled(colorMode string, isTerminal bool, getenv func(string) string) bool {
+	switch colorMode {
+	case colorModeAlways:
+		return true
+	case colorModeNever:
+		return false
+	}
+
+	if getenv("NO_COLOR") != "" {
+		return false
+	}
+	if force := getenv("CLICOLOR_FORCE"); force != "" && force != "0" {
+		return true
+	}
+	if getenv("CLICOLOR") == "0" || getenv("TERM") == "dumb" {
+		return false
+	}
+
+	return isTerminal
+}
+
+// ----------------------------------------------------------------------------
+// detect color depth of terminal from environment
+func detectColorDepth(getenv func(string) string) int {
+	switch strings.ToLower(getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return colorDepthTrue
+	}
+
+	if strings.Contains(getenv("TERM"), "256color") {
+		return colorDepth256
+	}
+
+	return colorDepth16
+}
@@ -8,6 +8,6 @@ import (
 	"os"
 )
 
-func getColorWriter(_ bool) terminalWriter {
-	return terminalWriter{writer: os.Stdout}
+func getColorWriter(caps terminalCaps) terminalWriter {
+	return terminalWriter{writer: os.Stdout, caps: caps}
 }
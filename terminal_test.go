@@ -0,0 +1,54 @@
+package main
+
+import "testing"
+
+func Test_colorEnabled(t *testing.T) {
+	tests := []struct {
+		name       string
+		colorMode  string
+		isTerminal bool
+		env        map[string]string
+		want       bool
+	}{
+		{"auto, terminal", colorModeAuto, true, nil, true},
+		{"auto, pipe", colorModeAuto, false, nil, false},
+		{"always, pipe", colorModeAlways, false, nil, true},
+		{"always with NO_COLOR", colorModeAlways, true, map[string]string{"NO_COLOR": "1"}, true},
+		{"never, terminal", colorModeNever, true, nil, false},
+		{"never with CLICOLOR_FORCE", colorModeNever, false, map[string]string{"CLICOLOR_FORCE": "1"}, false},
+		{"NO_COLOR", colorModeAuto, true, map[string]string{"NO_COLOR": "1"}, false},
+		{"CLICOLOR_FORCE, pipe", colorModeAuto, false, map[string]string{"CLICOLOR_FORCE": "1"}, true},
+		{"CLICOLOR_FORCE=0, pipe", colorModeAuto, false, map[string]string{"CLICOLOR_FORCE": "0"}, false},
+		{"NO_COLOR and CLICOLOR_FORCE", colorModeAuto, false, map[string]string{"NO_COLOR": "1", "CLICOLOR_FORCE": "1"}, false},
+		{"CLICOLOR=0", colorModeAuto, true, map[string]string{"CLICOLOR": "0"}, false},
+		{"dumb terminal", colorModeAuto, true, map[string]string{"TERM": "dumb"}, false},
+	}
+
+	for _, tt := range tests {
+		getenv := func(name string) string { return tt.env[name] }
+		if got := colorEnabled(tt.colorMode, tt.isTerminal, getenv); got != tt.want {
+			t.Errorf("%q. colorEnabled() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func Test_detectColorDepth(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want int
+	}{
+		{"empty", nil, colorDepth16},
+		{"xterm", map[string]string{"TERM": "xterm"}, colorDepth16},
+		{"256 colors", map[string]string{"TERM": "xterm-256color"}, colorDepth256},
+		{"truecolor", map[string]string{"TERM": "xterm-256color", "COLORTERM": "truecolor"}, colorDepthTrue},
+		{"24bit", map[string]string{"COLORTERM": "24bit"}, colorDepthTrue},
+	}
+
+	for _, tt := range tests {
+		getenv := func(name string) string { return tt.env[name] }
+		if got := detectColorDepth(getenv); got != tt.want {
+			t.Errorf("%q. detectColorDepth() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -7,9 +7,9 @@ import (
 	"github.com/mattn/go-colorable"
 )
 
-func getColorWriter(noColor bool) terminalWriter {
-	if noColor {
-		return terminalWriter{writer: os.Stdout}
+func getColorWriter(caps terminalCaps) terminalWriter {
+	if !caps.color {
+		return terminalWriter{writer: os.Stdout, caps: caps}
 	}
-	return terminalWriter{writer: colorable.NewColorableStdout()}
+	return terminalWriter{writer: colorable.NewColorableStdout(), caps: caps}
 }
@@ -0,0 +1,286 @@
+// color themes: named palettes and temperature gradient
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mgutz/ansi"
+)
+
+const (
+	// themeDefault - name of theme by default
+	themeDefault = "default"
+	// appConfigDirName - application directory in user config directory
+	appConfigDirName = "yandex-weather-cli"
+	// gradientTempMin - temperature for the first color of theme gradient
+	gradientTempMin = -30
+	// gradientTempMax - temperature for the last color of theme gradient
+	gradientTempMax = 40
+)
+
+// color depth of terminal
+const (
+	colorDepth16 = iota
+	colorDepth256
+	colorDepthTrue
+)
+
+// theme - named palette, roles are used as tags in ansiColourString(): "<value>...</>"
+type theme struct {
+	Roles    map[string]string // role name -> color tag, "value": "green"
+	Gradient []string          // "#rrggbb" colors from cold to hot temperature
+}
+
+// themes - built-in themes, custom themes are loaded from config file
+var themes = map[string]theme{
+	themeDefault: {
+		Roles: map[string]string{
+			"value":   "green",
+			"url":     "yellow",
+			"header":  "blue+h",
+			"weekend": "red+h",
+			"hours":   "grey+h",
+			"icon":    "blue",
+			"empty":   "grey+h",
+			"today":   "yellow+h",
+			"night":   "blue",
+			// weather warnings by severity and nowcast of precipitation
+			"warning_yellow": "yellow+h",
+			"warning_orange": "red+h",
+			"warning_red":    "red+bh",
+			"nowcast":        "yellow",
+		},
+		Gradient: []string{"#3050ff", "#00c8ff", "#40e080", "#f0e040", "#ff8020", "#e02020"},
+	},
+	"colorblind": {
+		// cividis-like palette, distinguishable with deuteranopia and protanopia
+		Roles: map[string]string{
+			"value":   "cyan+h",
+			"url":     "blue+h",
+			"header":  "blue+h",
+			"weekend": "yellow+h",
+			"hours":   "grey+h",
+			"icon":    "cyan",
+			"empty":   "grey+h",
+			"today":   "white+bh",
+			"night":   "blue+h",
+			// warnings without red, which is hard to distinguish
+			"warning_yellow": "yellow+h",
+			"warning_orange": "magenta+h",
+			"warning_red":    "magenta+bh",
+			"nowcast":        "cyan",
+		},
+		Gradient: []string{"#00204d", "#31446b", "#666970", "#958f78", "#cbba69", "#ffea46"},
+	},
+	"high-contrast": {
+		Roles: map[string]string{
+			"value":   "white+bh",
+			"url":     "yellow+bh",
+			"header":  "cyan+bh",
+			"weekend": "magenta+bh",
+			"hours":   "white+h",
+			"icon":    "cyan+bh",
+			"empty":   "white",
+			"today":   "yellow+bh",
+			"night":   "blue+bh",
+			// warnings on background of severity color
+			"warning_yellow": "black:yellow+h",
+			"warning_orange": "white+bh:magenta",
+			"warning_red":    "white+bh:red",
+			"nowcast":        "yellow+bh",
+		},
+		Gradient: []string{"#0000ff", "#00ffff", "#ffffff", "#ffff00", "#ff0000"},
+	},
+}
+
+// basicColors - RGB for 16 terminal colors (xterm), for fallback from 24-bit colors
+var basicColors = []struct {
+	name    string
+	r, g, b int
+}{
+	{"black", 0, 0, 0},
+	{"red", 205, 0, 0},
+	{"green", 0, 205, 0},
+	{"yellow", 205, 205, 0},
+	{"blue", 0, 0, 238},
+	{"magenta", 205, 0, 205},
+	{"cyan", 0, 205, 205},
+	{"white", 229, 229, 229},
+	{"black+h", 127, 127, 127},
+	{"red+h", 255, 0, 0},
+	{"green+h", 0, 255, 0},
+	{"yellow+h", 255, 255, 0},
+	{"blue+h", 92, 92, 255},
+	{"magenta+h", 255, 0, 255},
+	{"cyan+h", 0, 255, 255},
+	{"white+h", 255, 255, 255},
+}
+
+// ----------------------------------------------------------------------------
+// themes from sections of config file: [theme.name] with roles and gradient = ["#rrggbb", ...]
+func (cf configFile) themes() (map[string]theme, error) {
+	result := map[string]theme{}
+	for section, settings := range cf {
+		if !strings.HasPrefix(section, configThemePrefix) {
+			continue
+		}
+
+		th := theme{Roles: map[string]string{}}
+		for key, value := range settings {
+			if key != configKeyGradient {
+				th.Roles[key] = value
+				continue
+			}
+			gradient, err := parseConfigList(value)
+			if err != nil {
+				return nil, fmt.Errorf("[%s] %s: %s", section, key, err)
+			}
+			th.Gradient = gradient
+		}
+		result[strings.TrimPrefix(section, configThemePrefix)] = th
+	}
+
+	return result, nil
+}
+
+// ----------------------------------------------------------------------------
+// validate and add custom themes, missing roles and gradient are taken from default theme
+func addThemes(customThemes map[string]theme) error {
+	for name, th := range customThemes {
+		for _, color := range th.Gradient {
+			if _, _, _, err := parseHexColor(color); err != nil {
+				return fmt.Errorf("theme %q: %s", name, err)
+			}
+		}
+		for role, color := range th.Roles {
+			if err := checkColorTag(color); err != nil {
+				return fmt.Errorf("theme %q, role %q: %s", name, role, err)
+			}
+		}
+		if len(th.Gradient) == 0 {
+			th.Gradient = themes[themeDefault].Gradient
+		}
+
+		roles := map[string]string{}
+		for role, color := range themes[themeDefault].Roles {
+			roles[role] = color
+		}
+		for role, color := range th.Roles {
+			roles[role] = color
+		}
+		th.Roles = roles
+
+		themes[name] = th
+	}
+
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// names of all themes for usage
+func themesNames() string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return strings.Join(names, ", ")
+}
+
+// ----------------------------------------------------------------------------
+// get theme from config, default theme for unknown name
+func (cfg config) getTheme() theme {
+	if th, ok := themes[cfg.theme]; ok {
+		return th
+	}
+	return themes[themeDefault]
+}
+
+// ----------------------------------------------------------------------------
+// color tag for temperature on continuous gradient of theme: "#rrggbb"
+func (th theme) tempColor(temp float64) string {
+	if len(th.Gradient) == 0 {
+		return ""
+	}
+	if len(th.Gradient) == 1 {
+		return th.Gradient[0]
+	}
+
+	pos := (temp - gradientTempMin) / (gradientTempMax - gradientTempMin) * float64(len(th.Gradient)-1)
+	if pos <= 0 {
+		return th.Gradient[0]
+	}
+	if pos >= float64(len(th.Gradient)-1) {
+		return th.Gradient[len(th.Gradient)-1]
+	}
+
+	i := int(pos)
+	fraction := pos - float64(i)
+	r1, g1, b1, _ := parseHexColor(th.Gradient[i])
+	r2, g2, b2, _ := parseHexColor(th.Gradient[i+1])
+	mix := func(c1, c2 int) int {
+		return int(math.Round(float64(c1) + fraction*float64(c2-c1)))
+	}
+
+	return fmt.Sprintf("#%02x%02x%02x", mix(r1, r2), mix(g1, g2), mix(b1, b2))
+}
+
+// ----------------------------------------------------------------------------
+// check color of role: "#rrggbb" or color for ansi package, "white+bh:red"
+func checkColorTag(color string) error {
+	if strings.HasPrefix(color, "#") {
+		_, _, _, err := parseHexColor(color)
+		return err
+	}
+	if !reColorTag.MatchString(color) {
+		return fmt.Errorf("invalid color %q, want #rrggbb or color name with attributes: red, white+bh:red", color)
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// parse "#rrggbb" color
+func parseHexColor(color string) (r, g, b int, err error) {
+	if len(color) != 7 || color[0] != '#' {
+		return 0, 0, 0, fmt.Errorf("invalid color %q, want #rrggbb", color)
+	}
+
+	rgb, err := strconv.ParseUint(color[1:], 16, 32)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid color %q, want #rrggbb", color)
+	}
+
+	return int(rgb >> 16 & 0xff), int(rgb >> 8 & 0xff), int(rgb & 0xff), nil
+}
+
+// ----------------------------------------------------------------------------
+// ANSI code for "#rrggbb" color with fallback to 256 and 16 colors
+func hexColorCode(color string, colorDepth int) string {
+	r, g, b, err := parseHexColor(color)
+	if err != nil {
+		return ""
+	}
+
+	switch colorDepth {
+	case colorDepthTrue:
+		return fmt.Sprintf("\033[38;2;%d;%d;%dm", r, g, b)
+	case colorDepth256:
+		toCube := func(c int) int { return int(math.Round(float64(c) / 255 * 5)) }
+		return ansi.ColorCode(strconv.Itoa(16 + 36*toCube(r) + 6*toCube(g) + toCube(b)))
+	}
+
+	nearest, minDistance := "", math.MaxInt32
+	for _, basic := range basicColors {
+		distance := (r-basic.r)*(r-basic.r) + (g-basic.g)*(g-basic.g) + (b-basic.b)*(b-basic.b)
+		if distance < minDistance {
+			nearest, minDistance = basic.name, distance
+		}
+	}
+
+	return ansi.ColorCode(nearest)
+}
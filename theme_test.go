@@ -0,0 +1,153 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mgutz/ansi"
+)
+
+func Test_tempColor(t *testing.T) {
+	th := theme{Gradient: []string{"#0000ff", "#ffffff", "#ff0000"}}
+	tests := []struct {
+		temp float64
+		want string
+	}{
+		{-50, "#0000ff"},
+		{gradientTempMin, "#0000ff"},
+		{-12.5, "#8080ff"},
+		{5, "#ffffff"},
+		{22.5, "#ff8080"},
+		{gradientTempMax, "#ff0000"},
+		{50, "#ff0000"},
+	}
+
+	for _, tt := range tests {
+		if got := th.tempColor(tt.temp); got != tt.want {
+			t.Errorf("tempColor(%v) = %v, want %v", tt.temp, got, tt.want)
+		}
+	}
+
+	if got := (theme{}).tempColor(0); got != "" {
+		t.Errorf("tempColor() without gradient = %q, want empty", got)
+	}
+}
+
+func Test_hexColorCode(t *testing.T) {
+	tests := []struct {
+		name       string
+		color      string
+		colorDepth int
+		want       string
+	}{
+		{"truecolor", "#ff8000", colorDepthTrue, "\033[38;2;255;128;0m"},
+		{"256 colors", "#ff8000", colorDepth256, ansi.ColorCode("214")},
+		{"16 colors", "#ff8000", colorDepth16, ansi.ColorCode("yellow")},
+		{"16 colors, blue", "#1010e0", colorDepth16, ansi.ColorCode("blue")},
+		{"invalid", "#ff80", colorDepthTrue, ""},
+	}
+
+	for _, tt := range tests {
+		if got := hexColorCode(tt.color, tt.colorDepth); got != tt.want {
+			t.Errorf("%q. hexColorCode() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func Test_ansiColourStringThemes(t *testing.T) {
+	tests := []struct {
+		name  string
+		theme string
+		str   string
+		want  string
+	}{
+		{"role from default theme", "", "<value>str</>", ansi.ColorCode("green") + "str" + ansi.ColorCode("reset")},
+		{"role from theme", "high-contrast", "<value>str</value>", ansi.ColorCode("white+bh") + "str" + ansi.ColorCode("reset")},
+		{"hex color", "", "<#ff8000>str</>", "\033[38;2;255;128;0mstr" + ansi.ColorCode("reset")},
+		{"unknown tag", "", "<unknown>str", "<unknown>str"},
+	}
+
+	for _, tt := range tests {
+		cfg := config{theme: tt.theme, colorDepth: colorDepthTrue}
+		if got := cfg.ansiColourString(tt.str); got != tt.want {
+			t.Errorf("%q. ansiColourString() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func Test_addThemes(t *testing.T) {
+	err := addThemes(map[string]theme{"my": {Roles: map[string]string{"value": "magenta", "url": "#ff8000", "today": "white+bh:red"}}})
+	if err != nil {
+		t.Fatalf("addThemes() error: %s", err)
+	}
+	defer delete(themes, "my")
+
+	th := themes["my"]
+	if th.Roles["value"] != "magenta" || th.Roles["header"] != themes[themeDefault].Roles["header"] {
+		t.Errorf("addThemes() roles = %v, want merged with default theme", th.Roles)
+	}
+	if len(th.Gradient) != len(themes[themeDefault].Gradient) {
+		t.Errorf("addThemes() gradient = %v, want default gradient", th.Gradient)
+	}
+
+	for _, in := range []theme{
+		{Gradient: []string{"red"}},
+		{Roles: map[string]string{"value": "purple"}},
+		{Roles: map[string]string{"value": "red+x"}},
+		{Roles: map[string]string{"value": "#ff80"}},
+	} {
+		if err := addThemes(map[string]theme{"bad": in}); err == nil {
+			t.Errorf("addThemes(%v) want error", in)
+		}
+		delete(themes, "bad")
+	}
+}
+
+func Test_configFileThemes(t *testing.T) {
+	file, err := parseConfigFile(strings.NewReader(`
+theme = "my"
+[profile.home]
+city = "moscow"
+[theme.my]
+value = "magenta"
+warning_red = "white:red"
+gradient = ["#000000", "#ffffff"]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	customThemes, err := file.themes()
+	if err != nil {
+		t.Fatalf("themes() error: %s", err)
+	}
+	want := map[string]theme{
+		"my": {Roles: map[string]string{"value": "magenta", "warning_red": "white:red"}, Gradient: []string{"#000000", "#ffffff"}},
+	}
+	if !reflect.DeepEqual(customThemes, want) {
+		t.Errorf("themes() = %v, want %v", customThemes, want)
+	}
+
+	if err := addThemes(customThemes); err != nil {
+		t.Fatalf("addThemes() error: %s", err)
+	}
+	defer delete(themes, "my")
+	if th := themes["my"]; th.Roles["value"] != "magenta" || th.Roles["url"] != themes[themeDefault].Roles["url"] || len(th.Gradient) != 2 {
+		t.Errorf("addThemes() = %v, want merged with default theme", th)
+	}
+
+	for _, in := range []string{"[theme.bad]\ngradient = \"#000000\"", "[theme.bad]\ngradient = [\"red\"]"} {
+		file, err := parseConfigFile(strings.NewReader(in))
+		if err != nil {
+			t.Fatal(err)
+		}
+		customThemes, err := file.themes()
+		if err == nil {
+			err = addThemes(customThemes)
+		}
+		if err == nil {
+			t.Errorf("themes of %q want error", in)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// dates in time zone of city
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+	// time zones of cities without system tz database (Windows, minimal containers)
+	_ "time/tzdata"
+
+	"github.com/msoap/html2data"
+)
+
+// asOfLayouts - layouts of -as-of option, without time zone in time zone of city
+var asOfLayouts = []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02"}
+
+// nearestCityMaxDistance - maximum distance in km to city from gazetteer for time zone of coordinates
+const nearestCityMaxDistance = 300
+
+// pageTimeZoneLayout - date with offset of time zone in forecast of next days: "2021-10-22 00:00+0300"
+const pageTimeZoneLayout = "2006-01-02 15:04-0700"
+
+// ----------------------------------------------------------------------------
+// time zone of city from gazetteer, of nearest city for coordinates,
+// nil for unknown city or city detected by yandex, it is taken from page then
+func cityTimeZone(cfg config) *time.Location {
+	var (
+		city cityInfo
+		ok   bool
+	)
+	if cfg.location != nil {
+		var distance float64
+		city, distance = nearestCity(*cfg.location)
+		if distance > nearestCityMaxDistance {
+			return longitudeTimeZone(cfg.location.Lon)
+		}
+		ok = true
+	} else {
+		city, ok = lookupCity(cfg.city)
+	}
+
+	if !ok {
+		return nil
+	}
+
+	location, err := time.LoadLocation(city.TimeZone)
+	if err != nil {
+		return nil
+	}
+
+	return location
+}
+
+// ----------------------------------------------------------------------------
+// fixed time zone by offset of dates of next days on main page: "UTC+03:00", nil if there are no dates
+func pageTimeZone(doc html2data.Doc) *time.Location {
+	data, err := doc.GetDataFirst(map[string]string{"date": selectorsNextDays["date"]})
+	if err != nil {
+		return nil
+	}
+
+	date, err := time.Parse(pageTimeZoneLayout, clearNonprintInString(data["date"]))
+	if err != nil {
+		return nil
+	}
+
+	_, offset := date.Zone()
+	return time.FixedZone("UTC"+date.Format("-07:00"), offset)
+}
+
+// ----------------------------------------------------------------------------
+// approximate time zone by longitude: "UTC+3"
+func longitudeTimeZone(lon float64) *time.Location {
+	offset := int(math.Round(lon / 15))
+	return time.FixedZone(fmt.Sprintf("UTC%+d", offset), offset*3600)
+}
+
+// ----------------------------------------------------------------------------
+// current time in time zone of city
+func (cfg config) now() time.Time {
+	now := time.Now
+	if cfg.clock != nil {
+		now = cfg.clock
+	}
+
+	if cfg.timeZone == nil {
+		return now()
+	}
+	return now().In(cfg.timeZone)
+}
+
+// ----------------------------------------------------------------------------
+// today in time zone of city: "2006-01-02"
+func (cfg config) today() string {
+	return cfg.now().Format("2006-01-02")
+}
+
+// ----------------------------------------------------------------------------
+// parse date from page in time zone of city
+func (cfg config) parseDate(date string) (time.Time, error) {
+	location := cfg.timeZone
+	if location == nil {
+		location = time.Local
+	}
+
+	return time.ParseInLocation("2006-01-02", date, location)
+}
+
+// ----------------------------------------------------------------------------
+// clock of config stopped at moment of -as-of option in time zone of city,
+// in local time zone until time zone of city is taken from page
+func (cfg *config) setupAsOf() error {
+	location := cfg.timeZone
+	if location == nil {
+		location = time.Local
+	}
+
+	asOf, err := parseAsOf(cfg.asOf, location)
+	if err != nil {
+		return err
+	}
+	cfg.clock = func() time.Time { return asOf }
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// parse moment of -as-of option in time zone
+func parseAsOf(value string, location *time.Location) (time.Time, error) {
+	for _, layout := range asOfLayouts {
+		if moment, err := time.ParseInLocation(layout, value, location); err == nil {
+			return moment, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time %q for -as-of, want: 2006-01-02T15:04:05+03:00, 2006-01-02 15:04 or 2006-01-02", value)
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/msoap/html2data"
+)
+
+func Test_cityTimeZone(t *testing.T) {
+	tests := []struct {
+		name     string
+		city     string
+		location *geoPoint
+		want     string
+	}{
+		{name: "city", city: "vladivostok", want: "Asia/Vladivostok"},
+		{name: "city in Russian", city: "Токио", want: "Asia/Tokyo"},
+		{name: "coordinates near city", location: &geoPoint{Lat: 55.95, Lon: 36.5}, want: "Europe/Moscow"},
+		{name: "coordinates far from cities", location: &geoPoint{Lat: -40, Lon: -140}, want: "UTC-9"},
+		{name: "unknown city", city: "some-village", want: ""},
+		{name: "city by yandex", city: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		cfg := defaultConfig()
+		cfg.city, cfg.location = tt.city, tt.location
+		got := ""
+		if location := cityTimeZone(cfg); location != nil {
+			got = location.String()
+		}
+		if got != tt.want {
+			t.Errorf("%q. cityTimeZone() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func Test_pageTimeZone(t *testing.T) {
+	tests := []struct {
+		name       string
+		html       string
+		want       string
+		wantOffset int
+	}{
+		{
+			name:       "offset of first day",
+			html:       `<div class="forecast-briefly__days"><time class="time" datetime="2021-10-22 00:00+0300">22</time></div>`,
+			want:       "UTC+03:00",
+			wantOffset: 3 * 3600,
+		},
+		{
+			name:       "half hour offset",
+			html:       `<div class="forecast-briefly__days"><time class="time" datetime="2021-10-22 00:00+0530">22</time></div>`,
+			want:       "UTC+05:30",
+			wantOffset: 5*3600 + 30*60,
+		},
+		{
+			name: "date without offset",
+			html: `<div class="forecast-briefly__days"><time class="time" datetime="2021-10-22">22</time></div>`,
+		},
+		{
+			name: "no dates",
+			html: `<div class="fact"></div>`,
+		},
+	}
+
+	for _, tt := range tests {
+		location := pageTimeZone(html2data.FromReader(strings.NewReader(tt.html)))
+		if location == nil {
+			if tt.want != "" {
+				t.Errorf("%q. pageTimeZone() = nil, want %s", tt.name, tt.want)
+			}
+			continue
+		}
+		if _, offset := time.Date(2021, 10, 22, 0, 0, 0, 0, location).Zone(); location.String() != tt.want || offset != tt.wantOffset {
+			t.Errorf("%q. pageTimeZone() = %s %d, want %s %d", tt.name, location, offset, tt.want, tt.wantOffset)
+		}
+	}
+}
+
+func Test_fetchWeatherTimeZone(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.city, cfg.noToday = "some-village", true
+	cfg.page, cfg.asOf = "testdata/moscow.html", "2021-10-22 15:00"
+	if err := validateConfig(&cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.timeZone != nil {
+		t.Fatalf("time zone of unknown city = %s, want nil", cfg.timeZone)
+	}
+
+	if _, _, _, err := fetchWeather(&cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.timeZone == nil || cfg.timeZone.String() != "UTC+03:00" {
+		t.Errorf("time zone from page = %v, want UTC+03:00", cfg.timeZone)
+	}
+	if want := time.Date(2021, 10, 22, 12, 0, 0, 0, time.UTC); !cfg.now().Equal(want) {
+		t.Errorf("-as-of in time zone from page = %s, want %s", cfg.now(), want.In(cfg.timeZone))
+	}
+}
+
+func Test_distanceKm(t *testing.T) {
+	moscow, _ := lookupCity("moscow")
+	spb, _ := lookupCity("saint-petersburg")
+	got := distanceKm(geoPoint{Lat: moscow.Lat, Lon: moscow.Lon}, geoPoint{Lat: spb.Lat, Lon: spb.Lon})
+	if math.Abs(got-634) > 5 {
+		t.Errorf("distanceKm() Moscow - Saint Petersburg = %f", got)
+	}
+
+	if city, distance := nearestCity(geoPoint{Lat: 55.915, Lon: 36.86}); city.Slug != "istra" || distance > 1 {
+		t.Errorf("nearestCity() = %q, %f", city.Slug, distance)
+	}
+}
+
+func Test_parseNextDays(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Skip(err)
+	}
+	// late evening in Moscow, already next morning in Vladivostok
+	clock := func() time.Time { return time.Date(2021, 10, 22, 22, 30, 0, 0, moscow) }
+	data := map[string][]string{
+		"date":       {"2021-10-22", "2021-10-23", "2021-10-24", "2021-10-25"},
+		"desc":       {"Ясно", "Облачно", "Дождь", "Снег"},
+		"temp":       {"+10", "+8", "+5", "−1"},
+		"temp_night": {"+2", "+1", "0", "−5"},
+	}
+
+	tests := []struct {
+		city      string
+		daysLimit int
+		want      []dayForecast
+	}{
+		{
+			city:      "moscow",
+			daysLimit: 2,
+			want: []dayForecast{
+				{DateHuman: "23.10 (сб)", Date: "2021-10-23", Desc: "облачно", Temp: 8, TempNight: 1},
+				{DateHuman: "24.10 (вс)", Date: "2021-10-24", Desc: "дождь", Temp: 5, TempNight: 0},
+			},
+		},
+		{
+			city:      "vladivostok",
+			daysLimit: 10,
+			want: []dayForecast{
+				{DateHuman: "24.10 (вс)", Date: "2021-10-24", Desc: "дождь", Temp: 5, TempNight: 0},
+				{DateHuman: "25.10 (пн)", Date: "2021-10-25", Desc: "снег", Temp: -1, TempNight: -5},
+			},
+		},
+		{
+			city:      "london",
+			daysLimit: 10,
+			want: []dayForecast{
+				{DateHuman: "23.10 (сб)", Date: "2021-10-23", Desc: "облачно", Temp: 8, TempNight: 1},
+				{DateHuman: "24.10 (вс)", Date: "2021-10-24", Desc: "дождь", Temp: 5, TempNight: 0},
+				{DateHuman: "25.10 (пн)", Date: "2021-10-25", Desc: "снег", Temp: -1, TempNight: -5},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		cfg := defaultConfig()
+		cfg.city, cfg.daysLimit, cfg.clock = tt.city, tt.daysLimit, clock
+		cfg.timeZone = cityTimeZone(cfg)
+		if got := parseNextDays(data, cfg); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseNextDays() for %q = %+v, want %+v", tt.city, got, tt.want)
+		}
+	}
+}
+
+func Test_parseAsOf(t *testing.T) {
+	vladivostok, err := time.LoadLocation("Asia/Vladivostok")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "2021-10-22T15:04:05+03:00", want: time.Date(2021, 10, 22, 12, 4, 5, 0, time.UTC)},
+		{value: "2021-10-22T15:04", want: time.Date(2021, 10, 22, 15, 4, 0, 0, vladivostok)},
+		{value: "2021-10-22 15:04", want: time.Date(2021, 10, 22, 15, 4, 0, 0, vladivostok)},
+		{value: "2021-10-22", want: time.Date(2021, 10, 22, 0, 0, 0, 0, vladivostok)},
+		{value: "22.10.2021", wantErr: true},
+		{value: "yesterday", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseAsOf(tt.value, vladivostok)
+		if (err != nil) != tt.wantErr || !got.Equal(tt.want) {
+			t.Errorf("parseAsOf(%q) = %v, %v, want %v", tt.value, got, err, tt.want)
+		}
+	}
+}
@@ -4,6 +4,7 @@ package main
 import (
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/mgutz/ansi"
@@ -12,20 +13,26 @@ import (
 // HistoChars - chars for draw histogram
 var HistoChars = [...]string{"▁", "▂", "▃", "▄", "▅", "▆", "▇", "█"}
 
-var weekdaysRu = [...]string{
-	"вс",
-	"пн",
-	"вт",
-	"ср",
-	"чт",
-	"пт",
-	"сб",
-}
+// oneColorTag - foreground or background color with attributes
+const oneColorTag = `(black|red|green|yellow|blue|magenta|cyan|white|grey|\d{1,3})(\+[bBuih]+)?`
+
+// reColorTag - color for ansi package in tag or theme role: "red", "white+bh:red", "208"
+var reColorTag = regexp.MustCompile(`^` + oneColorTag + `(:` + oneColorTag + `)?$`)
+
+// lessThanTag - tag for "<" in escaped text, it is not a color
+const lessThanTag = "lt"
+
+// colorTagsEscaper - escape text for ansiColourString(), "<" of text isn't start of tag
+var colorTagsEscaper = strings.NewReplacer("<", "<"+lessThanTag+">")
 
 //-----------------------------------------------------------------------------
-// formatDates gets date in json and human format
-func formatDates(date time.Time) (formatDate string, jsonDate string) {
-	return date.Format("02.01") + " (" + weekdaysRu[date.Weekday()] + ")",
+// formatDates gets date in json and human format with weekday in language
+func formatDates(date time.Time, lang string) (formatDate string, jsonDate string) {
+	names, ok := weekdays[lang]
+	if !ok {
+		names = weekdays[langRu]
+	}
+	return date.Format("02.01") + " (" + names[date.Weekday()] + ")",
 		date.Format("2006-01-02")
 }
 
@@ -39,6 +46,29 @@ func convertStrToInt(str string) int {
 	return number
 }
 
+//-----------------------------------------------------------------------------
+// convert number from interface{} to float64, return 0 for other types
+func toFloat(value interface{}) float64 {
+	switch number := value.(type) {
+	case int:
+		return float64(number)
+	case float64:
+		return number
+	}
+	return 0
+}
+
+//-----------------------------------------------------------------------------
+// check that string is in list
+func inList(str string, list []string) bool {
+	for _, item := range list {
+		if item == str {
+			return true
+		}
+	}
+	return false
+}
+
 //-----------------------------------------------------------------------------
 func getMaxLengthDesc(list []dayForecast) int {
 	maxLengh := 0
@@ -74,18 +104,32 @@ func clearNonprintInString(in string) (out string) {
 }
 
 //-----------------------------------------------------------------------------
-// convert "<red>123</> str <green>456</green>" to ansi color string
+// convert "<red>123</> str <green>456</green>" to ansi color string,
+// also supported "<#rrggbb>" colors and theme roles: "<value>123</>",
+// "<lt>" is "<" of text escaped by colorTagsEscaper
 func (cfg config) ansiColourString(str string) string {
-	oneColor := `(black|red|green|yellow|blue|magenta|cyan|white|grey|\d{1,3})(\+[bBuih]+)?`
-	re := regexp.MustCompile(`<(` + oneColor + `(:` + oneColor + `)?|/\w*)>`)
+	roles := cfg.getTheme().Roles
+	re := regexp.MustCompile(`<(#[0-9a-fA-F]{6}|/\w*|[\w:+]+)>`)
 	result := re.ReplaceAllStringFunc(str, func(in string) (out string) {
-		if cfg.noColor {
-			return ""
+		tag := in[1 : len(in)-1]
+		if tag == lessThanTag {
+			return "<"
+		}
+		if color, ok := roles[tag]; ok {
+			tag = color
 		}
 
-		if tag := in[1 : len(in)-1]; tag[0] == '/' {
+		switch {
+		case tag[0] != '/' && tag[0] != '#' && !reColorTag.MatchString(tag):
+			// unknown tag, leave as is
+			return in
+		case cfg.noColor:
+			return ""
+		case tag[0] == '/':
 			out = ansi.ColorCode("reset")
-		} else {
+		case tag[0] == '#':
+			out = hexColorCode(tag, cfg.colorDepth)
+		default:
 			out = ansi.ColorCode(tag)
 		}
 
@@ -96,26 +140,10 @@ func (cfg config) ansiColourString(str string) string {
 }
 
 // ----------------------------------------------------------------------------
-// Render histogram for forecast by hours
-func renderHisto(forecastByHours []hourTemp) string {
+// Render histogram for forecast by hours, night hours are marked by "<night>" tag
+func renderHisto(forecastByHours []hourTemp, night []bool) string {
 	// linear interpolation (* 4)
-	interpolationFact := 4
-	temperatures := make([]float64, len(forecastByHours)*interpolationFact)
-	for i, row := range forecastByHours {
-		currTemp := float64(row.Temp)
-		nextI := i + 1
-		if i == len(forecastByHours)-1 {
-			nextI = i
-		}
-		nextTemp := float64(forecastByHours[nextI].Temp)
-
-		temperatures[i*interpolationFact] = currTemp
-
-		for j := 1; j < interpolationFact; j++ {
-			temperatures[i*interpolationFact+j] = currTemp +
-				(float64(j)/float64(interpolationFact))*((nextTemp-currTemp)/1)
-		}
-	}
+	temperatures := interpolateTemps(forecastByHours, 4)
 
 	minTemp, maxTemp := temperatures[0], temperatures[0]
 	result := ""
@@ -134,9 +162,16 @@ func renderHisto(forecastByHours []hourTemp) string {
 		// if difference between max and min is too small
 		maxTemp = minTemp + maxGradation/2
 	}
-	for _, temp := range temperatures {
+	for i, temp := range temperatures {
+		isNight := i/4 < len(night) && night[i/4]
+		if isNight && (i%4 == 0) {
+			result += "<night>"
+		}
 		reduceValue := int((temp - minTemp) / (maxTemp - minTemp) * maxGradation)
 		result += HistoChars[reduceValue]
+		if isNight && (i%4 == 3) {
+			result += "</><hours>"
+		}
 	}
 
 	return result
@@ -88,6 +88,7 @@ func Test_renderHisto(t *testing.T) {
 	tests := []struct {
 		name            string
 		forecastByHours []hourTemp
+		night           []bool
 		want            string
 	}{
 		{
@@ -166,6 +167,16 @@ func Test_renderHisto(t *testing.T) {
 			},
 			want: "▃▄▅▆█████▇▇▇▆▆▆▆▆▆▅▅▄▃▂▁▁▁▂▂▃▃▄▅▅▅▅▅▅▅▅▅▅▅▅▅▅▅▅▄▄▄▅▅▅▅▅▅",
 		},
+		{
+			name: "night hours",
+			forecastByHours: []hourTemp{
+				{Hour: 16, Temp: 2},
+				{Hour: 17, Temp: 1},
+				{Hour: 18, Temp: 0},
+			},
+			night: []bool{false, true, true},
+			want:  "▅▄▄▃<night>▃▂▂▁</><hours><night>▁▁▁▁</><hours>",
+		},
 		{
 			name: "all same temperature",
 			forecastByHours: []hourTemp{
@@ -211,12 +222,12 @@ func Test_renderHisto(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		if got := renderHisto(tt.forecastByHours); got != tt.want {
+		if got := renderHisto(tt.forecastByHours, tt.night); got != tt.want {
 			t.Errorf("%q. renderHisto() = %v, want %v", tt.name, got, tt.want)
 		}
 	}
 }
 
 func Test_getColorWriter(t *testing.T) {
-	getColorWriter(true)
+	getColorWriter(terminalCaps{})
 }
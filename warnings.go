@@ -0,0 +1,128 @@
+// official weather warnings and nowcast of precipitation near current weather
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/msoap/html2data"
+)
+
+// exitCodeAlert - exit code when there are warnings of severity from -alert option,
+// 1 is used for errors and 2 for invalid options
+const exitCodeAlert = 3
+
+// severities of warnings from lowest
+const (
+	severityYellow = "yellow"
+	severityOrange = "orange"
+	severityRed    = "red"
+)
+
+// warningSeverities - severities of warnings from lowest
+var warningSeverities = []string{severityYellow, severityOrange, severityRed}
+
+// selectorWarning - root element of one warning
+var selectorWarning = "div.fact__warnings div.warning"
+
+// selectorsWarning - css selectors for warning, severity is in class of icon: "warning__icon_severity_red"
+var selectorsWarning = map[string]string{
+	"title":    "div.warning__title",
+	"text":     "div.warning__text",
+	"severity": "i.warning__icon:attr(class)",
+}
+
+// selectorsNowcast - css selectors for nowcast: "в ближайшие 2 часа осадков не ожидается"
+var selectorsNowcast = map[string]string{
+	"nowcast": "div.fact__nowcast div.fact__nowcast-alert",
+}
+
+// reSeverity - severity in class of warning icon
+var reSeverity = regexp.MustCompile(`\bwarning__icon_severity_(\w+)`)
+
+// weatherWarning - official weather warning
+type weatherWarning struct {
+	Severity string `json:"severity"` // yellow, orange or red
+	Title    string `json:"title,omitempty"`
+	Text     string `json:"text"`
+}
+
+// ----------------------------------------------------------------------------
+// parse warnings from main page, unknown severity is yellow
+func parseWarnings(doc html2data.Doc) []weatherWarning {
+	items, err := doc.GetDataNestedFirst(selectorWarning, selectorsWarning)
+	if err != nil {
+		return nil
+	}
+
+	result := []weatherWarning{}
+	for _, item := range items {
+		warning := weatherWarning{
+			Severity: severityYellow,
+			Title:    clearNonprintInString(item["title"]),
+			Text:     clearNonprintInString(item["text"]),
+		}
+		if match := reSeverity.FindStringSubmatch(item["severity"]); match != nil && inList(match[1], warningSeverities) {
+			warning.Severity = match[1]
+		}
+		if warning.Text == "" && warning.Title == "" {
+			continue
+		}
+		result = append(result, warning)
+	}
+
+	return result
+}
+
+// ----------------------------------------------------------------------------
+// parse nowcast text from main page, empty if the page doesn't have it
+func parseNowcast(doc html2data.Doc) string {
+	data, err := doc.GetDataFirst(selectorsNowcast)
+	if err != nil {
+		return ""
+	}
+	return clearNonprintInString(data["nowcast"])
+}
+
+// ----------------------------------------------------------------------------
+// rank of severity, -1 for unknown
+func severityRank(severity string) int {
+	for i, item := range warningSeverities {
+		if item == severity {
+			return i
+		}
+	}
+	return -1
+}
+
+// ----------------------------------------------------------------------------
+// exit code for warnings: exitCodeAlert if any warning has severity of -alert option or higher
+func alertExitCode(warnings []weatherWarning, cfg config) int {
+	if cfg.alert == "" {
+		return 0
+	}
+
+	for _, warning := range warnings {
+		if severityRank(warning.Severity) >= severityRank(cfg.alert) {
+			return exitCodeAlert
+		}
+	}
+	return 0
+}
+
+// ----------------------------------------------------------------------------
+// lines of warnings and nowcast for top of output, colored by theme role of severity,
+// severity is also a word for output without colors
+func renderWarnings(warnings []weatherWarning, nowcast string, cfg config) []string {
+	result := []string{}
+	for _, warning := range warnings {
+		level := fmt.Sprintf(cfg.tr("warning_severity"), cfg.tr("severity_"+warning.Severity))
+		result = append(result, fmt.Sprintf("<warning_%s>⚠ %s</>", warning.Severity, strings.Join(nonEmpty(level, warning.Title, warning.Text), ": ")))
+	}
+	if nowcast != "" {
+		result = append(result, "<nowcast>» "+nowcast+"</>")
+	}
+
+	return result
+}
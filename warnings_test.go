@@ -0,0 +1,96 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/msoap/html2data"
+)
+
+func Test_parseWarnings(t *testing.T) {
+	html := `<div class="fact__warnings">
+		<div class="warning"><i class="icon warning__icon warning__icon_severity_red"></i>
+			<div class="warning__title">Ураган</div><div class="warning__text">Порывы до 30 м/с</div></div>
+		<div class="warning"><i class="icon warning__icon"></i><div class="warning__text">Гололедица</div></div>
+		<div class="warning"><i class="icon warning__icon warning__icon_severity_orange"></i></div>
+	</div>
+	<div class="fact"><div class="fact__nowcast"><div class="fact__nowcast-alert">
+		В ближайшие 2 часа осадков не ожидается
+	</div></div></div>`
+	doc := html2data.FromReader(strings.NewReader(html))
+
+	want := []weatherWarning{
+		{Severity: severityRed, Title: "Ураган", Text: "Порывы до 30 м/с"},
+		{Severity: severityYellow, Text: "Гололедица"},
+	}
+	if got := parseWarnings(doc); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseWarnings() = %+v, want %+v", got, want)
+	}
+	if got := parseNowcast(doc); got != "В ближайшие 2 часа осадков не ожидается" {
+		t.Errorf("parseNowcast() = %q", got)
+	}
+
+	empty := html2data.FromReader(strings.NewReader(`<div class="fact"></div>`))
+	if got := parseWarnings(empty); len(got) != 0 {
+		t.Errorf("parseWarnings() without warnings = %+v", got)
+	}
+	if got := parseNowcast(empty); got != "" {
+		t.Errorf("parseNowcast() without nowcast = %q", got)
+	}
+}
+
+func Test_alertExitCode(t *testing.T) {
+	warnings := []weatherWarning{{Severity: severityYellow}, {Severity: severityOrange}}
+
+	tests := []struct {
+		alert    string
+		warnings []weatherWarning
+		want     int
+	}{
+		{alert: "", warnings: warnings, want: 0},
+		{alert: severityYellow, warnings: warnings, want: exitCodeAlert},
+		{alert: severityOrange, warnings: warnings, want: exitCodeAlert},
+		{alert: severityRed, warnings: warnings, want: 0},
+		{alert: severityYellow, warnings: nil, want: 0},
+	}
+
+	for _, tt := range tests {
+		cfg := defaultConfig()
+		cfg.alert = tt.alert
+		if got := alertExitCode(tt.warnings, cfg); got != tt.want {
+			t.Errorf("alertExitCode() with -alert %q = %d, want %d", tt.alert, got, tt.want)
+		}
+	}
+}
+
+func Test_renderWarnings(t *testing.T) {
+	warnings := []weatherWarning{
+		{Severity: severityYellow, Title: "Сильный ветер"},
+		{Severity: severityRed, Title: "Гроза", Text: "ночью"},
+	}
+
+	cfg := defaultConfig()
+	cfg.lang = langEn
+	got := renderWarnings(warnings, "no precipitation", cfg)
+	want := []string{
+		"<warning_yellow>⚠ yellow level: Сильный ветер</>",
+		"<warning_red>⚠ red level: Гроза: ночью</>",
+		"<nowcast>» no precipitation</>",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("renderWarnings() = %q, want %q", got, want)
+	}
+
+	for name, th := range themes {
+		for _, role := range []string{"warning_yellow", "warning_orange", "warning_red", "nowcast"} {
+			if _, ok := th.Roles[role]; !ok {
+				t.Errorf("theme %q has no role %q", name, role)
+			}
+		}
+		cfg.theme = name
+		if colored := cfg.ansiColourString(got[1]); strings.Contains(colored, "<") {
+			t.Errorf("theme %q, warning has unknown color: %q", name, colored)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// window of days for forecast by days: today, offset, date range and weekends
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// dateLayout - layout of dates in -from/-to options and JSON
+const dateLayout = "2006-01-02"
+
+// ----------------------------------------------------------------------------
+// check options of days window
+func validateDaysWindow(cfg config) error {
+	if cfg.daysOffset < 0 {
+		return fmt.Errorf("invalid offset %d, want 0 or more days", cfg.daysOffset)
+	}
+
+	for name, value := range map[string]string{"from": cfg.dateFrom, "to": cfg.dateTo} {
+		if value == "" {
+			continue
+		}
+		if _, err := time.Parse(dateLayout, value); err != nil {
+			return fmt.Errorf("invalid date %q for -%s, want: 2006-01-02", value, name)
+		}
+	}
+
+	if cfg.dateFrom != "" && cfg.daysOffset > 0 {
+		return fmt.Errorf("-offset %d can't be used with -from %s", cfg.daysOffset, cfg.dateFrom)
+	}
+
+	if cfg.dateFrom != "" && cfg.dateTo != "" && cfg.dateFrom > cfg.dateTo {
+		return fmt.Errorf("-from %s is after -to %s", cfg.dateFrom, cfg.dateTo)
+	}
+
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// first day of window: -from date, or today/tomorrow shifted by -offset days (not used with -from)
+func (cfg config) firstDay() string {
+	if cfg.dateFrom != "" {
+		return cfg.dateFrom
+	}
+
+	days := cfg.daysOffset
+	if !cfg.includeToday {
+		days++
+	}
+	return cfg.now().AddDate(0, 0, days).Format(dateLayout)
+}
+
+// ----------------------------------------------------------------------------
+// check that day of forecast is in window
+func (cfg config) inDaysWindow(date time.Time) bool {
+	day := date.Format(dateLayout)
+	if day < cfg.firstDay() || cfg.dateTo != "" && day > cfg.dateTo {
+		return false
+	}
+
+	if cfg.weekendsOnly && date.Weekday() != time.Saturday && date.Weekday() != time.Sunday {
+		return false
+	}
+
+	return true
+}
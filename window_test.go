@@ -0,0 +1,71 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_validateDaysWindow(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config
+		wantErr bool
+	}{
+		{"default", config{}, false},
+		{"range", config{dateFrom: "2021-10-22", dateTo: "2021-10-25"}, false},
+		{"one day", config{dateFrom: "2021-10-22", dateTo: "2021-10-22"}, false},
+		{"negative offset", config{daysOffset: -1}, true},
+		{"invalid from", config{dateFrom: "22.10.2021"}, true},
+		{"invalid to", config{dateTo: "2021-13-01"}, true},
+		{"from after to", config{dateFrom: "2021-10-25", dateTo: "2021-10-22"}, true},
+		{"from with offset", config{dateFrom: "2021-10-22", daysOffset: 2}, true},
+		{"to with offset", config{dateTo: "2021-10-25", daysOffset: 2}, false},
+	}
+
+	for _, tt := range tests {
+		if err := validateDaysWindow(tt.cfg); (err != nil) != tt.wantErr {
+			t.Errorf("%q. validateDaysWindow() error = %v, want error: %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func Test_daysWindow(t *testing.T) {
+	// Friday
+	clock := func() time.Time { return time.Date(2021, 10, 22, 15, 0, 0, 0, time.UTC) }
+	data := map[string][]string{
+		"date": {"2021-10-21", "2021-10-22", "2021-10-23", "2021-10-24", "2021-10-25", "2021-10-26", "2021-10-30", "2021-10-31"},
+	}
+
+	tests := []struct {
+		name string
+		cfg  config
+		want []string
+	}{
+		{"after today by default", config{}, []string{"2021-10-23", "2021-10-24", "2021-10-25", "2021-10-26", "2021-10-30", "2021-10-31"}},
+		{"include today", config{includeToday: true}, []string{"2021-10-22", "2021-10-23", "2021-10-24", "2021-10-25", "2021-10-26", "2021-10-30", "2021-10-31"}},
+		{"offset", config{daysOffset: 2}, []string{"2021-10-25", "2021-10-26", "2021-10-30", "2021-10-31"}},
+		{"offset from today", config{includeToday: true, daysOffset: 1}, []string{"2021-10-23", "2021-10-24", "2021-10-25", "2021-10-26", "2021-10-30", "2021-10-31"}},
+		{"range", config{dateFrom: "2021-10-24", dateTo: "2021-10-26"}, []string{"2021-10-24", "2021-10-25", "2021-10-26"}},
+		{"from in past", config{dateFrom: "2021-10-21", dateTo: "2021-10-22"}, []string{"2021-10-21", "2021-10-22"}},
+		{"weekends", config{weekendsOnly: true}, []string{"2021-10-23", "2021-10-24", "2021-10-30", "2021-10-31"}},
+		{"weekends with limit", config{weekendsOnly: true, daysLimit: 3}, []string{"2021-10-23", "2021-10-24", "2021-10-30"}},
+		{"weekends in range", config{weekendsOnly: true, dateTo: "2021-10-29"}, []string{"2021-10-23", "2021-10-24"}},
+	}
+
+	for _, tt := range tests {
+		cfg := tt.cfg
+		cfg.clock, cfg.timeZone = clock, time.UTC
+		if cfg.daysLimit == 0 {
+			cfg.daysLimit = 10
+		}
+
+		got := []string{}
+		for _, day := range parseNextDays(data, cfg) {
+			got = append(got, day.Date)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q. days = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -19,11 +19,9 @@ package main
 
 import (
 	"encoding/json"
-	"flag"
 	"fmt"
 	"os"
 	"regexp"
-	"runtime"
 	"strings"
 	"sync"
 	"time"
@@ -33,29 +31,84 @@ import (
 
 // config - application config
 type config struct {
-	baseURL     string
-	baseURLMini string
-	city        string
-	getJSON     bool
-	noColor     bool
-	noToday     bool
-	daysLimit   int
+	baseURL        string
+	baseURLMini    string
+	city           string
+	lat            string
+	lon            string
+	location       *geoPoint
+	timeZone       *time.Location   // time zone of city for dates
+	clock          func() time.Time // current time, time.Now by default
+	asOf           string           // render as if it were this moment
+	page           string           // saved main page instead of download
+	pageMini       string           // saved page for forecast by hours
+	pageDetails    string           // saved details page for -detailed
+	detailed       bool
+	hoursDays      int
+	alert          string // severity of warnings for exitCodeAlert
+	tempMin        int    // scoring profile of best-day command
+	tempMax        int
+	windMax        int
+	dry            bool
+	preferWeekends bool
+	comfort        bool
+	getJSON        bool
+	summary        bool
+	accessible     bool
+	oneline        bool
+	template       string // template of -oneline output
+	noColor        bool
+	colorMode      string
+	terminal       terminalCaps
+	noToday        bool
+	daysLimit      int
+	includeToday   bool
+	daysOffset     int
+	dateFrom       string
+	dateTo         string
+	weekendsOnly   bool
+	chartHeight    int
+	braille        bool
+	daysChart      bool
+	theme          string
+	colorDepth     int
+	units          string
+	lang           string
+	format         string
+	cacheTTL       time.Duration
+	deadline       time.Duration // network deadline for all pages of run, cached page of any age is used after it
+	interval       time.Duration // refresh interval of status bar output
+	profile        string
+	configPath     string
+	getVersion     bool
+	view           string
+	sources        map[string]string
+	favorites      []string
 }
 
 // hourTemp - one hour temperature
 type hourTemp struct {
-	Hour int    `json:"hour"`
-	Temp int    `json:"temp"`
-	Icon string `json:"icon"`
+	Date       string   `json:"date,omitempty"` // date of day for forecast by hours for several days
+	Hour       int      `json:"hour"`
+	Temp       int      `json:"temp"`
+	Icon       string   `json:"icon"`
+	PrecipProb *int     `json:"precip_prob,omitempty"` // probability of precipitation, %
+	Precip     *float64 `json:"precip,omitempty"`      // precipitation, mm
+	Wind       *float64 `json:"wind,omitempty"`        // wind speed, m/s
+	FeelsLike  *int     `json:"feels_like,omitempty"`
 }
 
 // dayForecast - one day forecast
 type dayForecast struct {
-	DateHuman string `json:"-"`
-	Date      string `json:"date"`
-	Desc      string `json:"desc"`
-	Temp      int    `json:"temp"`
-	TempNight int    `json:"temp_night"`
+	DateHuman string     `json:"-"`
+	Date      string     `json:"date"`
+	Desc      string     `json:"desc"`
+	Temp      int        `json:"temp"`
+	TempNight int        `json:"temp_night"`
+	Parts     []dayPart  `json:"parts,omitempty"`
+	Astro     *astroInfo `json:"astro,omitempty"`
+
+	noTempNight bool // page has no night temperature, TempNight is 0
 }
 
 var (
@@ -68,10 +121,36 @@ const (
 	envBaseURLName = "Y_WEATHER_URL"
 	// envBaseURLMiniName - environment variable for setup base URL (for days forecast)
 	envBaseURLMiniName = "Y_WEATHER_MINI_URL"
+	// envThemeName - environment variable for setup color theme
+	envThemeName = "Y_WEATHER_THEME"
+	// envProfileName - environment variable for profile from config file
+	envProfileName = "Y_WEATHER_PROFILE"
+	// envConfigName - environment variable for path of config file
+	envConfigName = "Y_WEATHER_CONFIG"
 	// baseURLDefault - yandex pogoda service url (testing: "http://localhost:8080/get?url=https://yandex.ru/pogoda/")
 	baseURLDefault = "https://yandex.ru/pogoda/"
 	// baseURLMiniDefault - url for forecast by hours (testing: "http://localhost:8080/get?url=https://p.ya.ru/")
 	baseURLMiniDefault = "https://p.ya.ru/"
+	// appName - name of program for usage
+	appName = "yandex-weather-cli"
+	// formatText - output as text tables
+	formatText = "text"
+	// formatJSON - output as JSON
+	formatJSON = "json"
+	// formatSummary - output as few sentences
+	formatSummary = "summary"
+	// formatAccessible - output as labelled statements for screen readers
+	formatAccessible = "accessible"
+	// formatOneline - output as one line by template, for shell prompts
+	formatOneline = "oneline"
+	// formatWaybar, formatI3bar, formatPolybar - output for status bars
+	formatWaybar  = "waybar"
+	formatI3bar   = "i3bar"
+	formatPolybar = "polybar"
+	// viewNow, viewHours, viewDays - parts of forecast for commands, all by default
+	viewNow   = "now"
+	viewHours = "hours"
+	viewDays  = "days"
 	// todayForecastTableWidth - today forecast table width for align tables
 	todayForecastTableWidth = 14*4 - 27
 )
@@ -99,9 +178,13 @@ var selectorByHoursRoot = "div.temp-chart__wrap"
 
 // selectorByHours - get forecast by hours
 var selectorByHours = map[string]string{
-	"hour": "p.temp-chart__hour",
-	"temp": "div.temp-chart__temp",
-	"icon": "i.icon:attr(class)",
+	"hour":        "p.temp-chart__hour",
+	"temp":        "div.temp-chart__temp",
+	"icon":        "i.icon:attr(class)",
+	"precip_prob": "div.temp-chart__prec-prob",
+	"precip":      "div.temp-chart__prec",
+	"wind":        "div.temp-chart__wind",
+	"feels_like":  "div.temp-chart__feels-like",
 }
 
 // icons - unicode symbols for icon names
@@ -111,74 +194,238 @@ var icons = map[string]string{
 }
 
 //-----------------------------------------------------------------------------
-// check if program's output used in *nix pipe
-func outputIsPiped() bool {
-	stdoutStat, err := os.Stdout.Stat()
-	return err != nil || (stdoutStat.Mode()&os.ModeCharDevice) == 0
+// config with default values
+func defaultConfig() config {
+	return config{
+		baseURL:     baseURLDefault,
+		baseURLMini: baseURLMiniDefault,
+		colorMode:   colorModeAuto,
+		daysLimit:   10,
+		tempMin:     15,
+		tempMax:     25,
+		windMax:     8,
+		dry:         true,
+		theme:       themeDefault,
+		units:       unitsCelsius,
+		lang:        langRu,
+		format:      formatText,
+		template:    onelineTemplateDefault,
+		configPath:  defaultConfigPath(),
+	}
 }
 
 //-----------------------------------------------------------------------------
-// get command line parameters
-func getParams() (cfg config) {
-	flag.BoolVar(&cfg.getJSON, "json", false, "get JSON")
-	flag.BoolVar(&cfg.noColor, "no-color", false, "disable colored output")
-	flag.BoolVar(&cfg.noToday, "no-today", false, "disable today forecast")
-	flag.IntVar(&cfg.daysLimit, "days", 10, "maximum days to show")
-	flag.Usage = func() {
-		fmt.Printf("Usage: %s [options] [city]\noptions:\n", os.Args[0])
-		flag.PrintDefaults()
-		fmt.Printf("\nexamples:\n  %s kyiv\n  %s -json london\n", os.Args[0], os.Args[0])
+// merge flags from command line with environment and config file:
+// flags, then environment, then profile, then config file defaults, then built-in defaults
+func getParams(flagsSettings map[string]string) (cfg config, err error) {
+	cfg = defaultConfig()
+	if configPath := os.Getenv(envConfigName); configPath != "" {
+		cfg.configPath = configPath
+	}
+	if configPath, ok := flagsSettings["config"]; ok {
+		cfg.configPath = configPath
+	}
+	delete(flagsSettings, "config")
+
+	file, err := readConfigFile(cfg.configPath)
+	if err != nil {
+		return cfg, err
+	}
+	fileSettings := map[string]string{}
+	for key, value := range file[""] {
+		fileSettings[key] = value
+	}
+	delete(fileSettings, configKeyProfile)
+	delete(fileSettings, configKeyFavorites)
+	if favorites, ok := file[""][configKeyFavorites]; ok {
+		if cfg.favorites, err = parseConfigList(favorites); err != nil {
+			return cfg, fmt.Errorf("%s: %s", configKeyFavorites, err)
+		}
+	}
+	customThemes, err := file.themes()
+	if err == nil {
+		err = addThemes(customThemes)
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("%s: %s", cfg.configPath, err)
 	}
-	getVersion := flag.Bool("version", false, "get version")
-	flag.Parse()
 
-	if *getVersion {
-		fmt.Println(version)
-		os.Exit(0)
+	if profile, ok := flagsSettings["profile"]; ok {
+		cfg.profile = profile
+	} else if profile := os.Getenv(envProfileName); profile != "" {
+		cfg.profile = profile
+	} else {
+		cfg.profile = file[""][configKeyProfile]
+	}
+	delete(flagsSettings, "profile")
+	profileSettings, err := file.profile(cfg.profile)
+	if err != nil {
+		return cfg, err
 	}
 
-	cfg.city = ""
-	if flag.NArg() >= 1 {
-		cfg.city = flag.Args()[0]
+	envSettings := map[string]string{}
+	if theme := os.Getenv(envThemeName); theme != "" {
+		envSettings["theme"] = theme
 	}
 
-	if runtime.GOOS == "windows" {
-		// broken unicode symbols in cmd.exe and don't detect pipe
-		cfg.noToday = true
-	} else if outputIsPiped() {
-		cfg.noColor = true
+	cfg.sources, err = mergeSettings(newFlagSet(&cfg), &cfg, []settingsLayer{
+		{source: sourceConfig, settings: fileSettings},
+		{source: sourceProfile, settings: profileSettings},
+		{source: sourceEnv, settings: envSettings},
+		{source: sourceFlag, settings: flagsSettings},
+	})
+	if err != nil {
+		return cfg, err
 	}
 
+	cfg.city = resolveCity(cfg.city)
+
 	if baseURL := os.Getenv(envBaseURLName); len(baseURL) > 0 {
 		cfg.baseURL = baseURL
-	} else {
-		cfg.baseURL = baseURLDefault
 	}
 	if baseURLMini := os.Getenv(envBaseURLMiniName); len(baseURLMini) > 0 {
 		cfg.baseURLMini = baseURLMini
-	} else {
-		cfg.baseURLMini = baseURLMiniDefault
 	}
 
-	return cfg
+	if err := validateConfig(&cfg); err != nil {
+		return cfg, err
+	}
+	setupTerminal(&cfg)
+
+	return cfg, nil
+}
+
+//-----------------------------------------------------------------------------
+// check values of options after merge
+func validateConfig(cfg *config) error {
+	formatFlags := []struct {
+		set    bool
+		format string
+	}{
+		{cfg.getJSON, formatJSON},
+		{cfg.summary, formatSummary},
+		{cfg.accessible, formatAccessible},
+		{cfg.oneline, formatOneline},
+	}
+	formatFlag := ""
+	for _, item := range formatFlags {
+		if !item.set {
+			continue
+		}
+		if formatFlag != "" {
+			return fmt.Errorf("-%s and -%s can't be used together", formatFlag, item.format)
+		}
+		formatFlag, cfg.format = item.format, item.format
+	}
+	cfg.getJSON = cfg.format == formatJSON
+	cfg.summary = cfg.format == formatSummary
+	cfg.accessible = cfg.format == formatAccessible
+	cfg.oneline = cfg.format == formatOneline
+	if cfg.oneline {
+		setupOneline(cfg)
+	}
+	if inList(cfg.format, barFormats) {
+		setupBar(cfg)
+	}
+
+	checks := []struct {
+		name    string
+		value   string
+		allowed []string
+	}{
+		{"color mode", cfg.colorMode, []string{colorModeAuto, colorModeAlways, colorModeNever}},
+		{"units", cfg.units, []string{unitsCelsius, unitsFahrenheit}},
+		{"language", cfg.lang, []string{langRu, langEn}},
+		{"format", cfg.format, []string{formatText, formatJSON, formatSummary, formatAccessible, formatOneline, formatWaybar, formatI3bar, formatPolybar}},
+	}
+	for _, check := range checks {
+		if !inList(check.value, check.allowed) {
+			return fmt.Errorf("invalid %s %q, want: %s", check.name, check.value, strings.Join(check.allowed, ", "))
+		}
+	}
+
+	if _, ok := themes[cfg.theme]; !ok {
+		return fmt.Errorf("theme %q not found, available: %s", cfg.theme, themesNames())
+	}
+
+	if cfg.alert != "" && !inList(cfg.alert, warningSeverities) {
+		return fmt.Errorf("invalid alert severity %q, want: %s", cfg.alert, strings.Join(warningSeverities, ", "))
+	}
+
+	if cfg.tempMin > cfg.tempMax {
+		return fmt.Errorf("invalid temperature range %d..%d, -temp-min must not be greater than -temp-max", cfg.tempMin, cfg.tempMax)
+	}
+
+	if cfg.hoursDays < 0 {
+		return fmt.Errorf("invalid hours days %d, want 0 or more days", cfg.hoursDays)
+	}
+
+	if err := validateDaysWindow(*cfg); err != nil {
+		return err
+	}
+
+	location, err := parseLocation(*cfg)
+	if err != nil {
+		return err
+	}
+	cfg.location = location
+	cfg.timeZone = cityTimeZone(*cfg)
+
+	if cfg.asOf != "" {
+		if err := cfg.setupAsOf(); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 //-----------------------------------------------------------------------------
 // parse html via goquery, find DOM-nodes with weather forecast data
-func getWeather(cfg config) (map[string]interface{}, []hourTemp, []dayForecast) {
+func getWeather(cfg *config) (map[string]interface{}, []hourTemp, []dayForecast) {
+	forecastNow, forecastByHours, forecastNext, err := fetchWeather(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	return forecastNow, forecastByHours, forecastNext
+}
+
+//-----------------------------------------------------------------------------
+// download and parse forecast, returns error if the main page can't be parsed,
+// unknown time zone of city is set from the main page (local if the page has no dates),
+// before -as-of moment and dates of other pages are parsed in it
+func fetchWeather(cfgPtr *config) (map[string]interface{}, []hourTemp, []dayForecast, error) {
+	// deadline is shared by the main page and the others after it
+	ctx, cancel := deadlineContext(*cfgPtr)
+	defer cancel()
+
+	var mainDoc *html2data.Doc
+	if cfgPtr.timeZone == nil {
+		doc := getPageDoc(ctx, cfgPtr.page, cfgPtr.pageURL(cfgPtr.baseURL), *cfgPtr)
+		mainDoc = &doc
+		if cfgPtr.timeZone = pageTimeZone(doc); cfgPtr.timeZone == nil {
+			cfgPtr.timeZone = time.Local
+		}
+		if cfgPtr.asOf != "" {
+			if err := cfgPtr.setupAsOf(); err != nil {
+				return nil, nil, nil, err
+			}
+		}
+	}
+	cfg := *cfgPtr
+
 	forecastNow := map[string]interface{}{}
 	forecastNext := []dayForecast{}
 	forecastByHours := []hourTemp{}
 
 	reRemoveDesc := regexp.MustCompile(`^.+\s*:\s*`)
 	reRemoveMultiline := regexp.MustCompile(`\n.+$`)
-	reDate := regexp.MustCompile(`^\d{4}-\d{2}-\d{2}`)
 
-	var extractNowForecast = func(doc html2data.Doc) {
+	var extractNowForecast = func(doc html2data.Doc) error {
 		data, err := doc.GetDataFirst(selectors)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			os.Exit(1)
+			return err
 		}
 
 		for name := range selectors {
@@ -197,83 +444,57 @@ func getWeather(cfg config) (map[string]interface{}, []hourTemp, []dayForecast)
 				forecastNow[name] = "0 м/с"
 			}
 		}
+
+		if extra, ok := parseExtraPanels(doc); ok {
+			forecastNow["extra"] = extra
+		}
+		if warnings := parseWarnings(doc); len(warnings) > 0 {
+			forecastNow["warnings"] = warnings
+		}
+		if nowcast := parseNowcast(doc); nowcast != "" {
+			forecastNow["nowcast"] = nowcast
+		}
+		return nil
 	}
 
-	var extractNextForecast = func(doc html2data.Doc) {
+	var extractNextForecast = func(doc html2data.Doc) error {
 		dataNextDays, err := doc.GetData(selectorsNextDays)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			os.Exit(1)
+			return err
 		}
 
-		if dateColumn, ok := dataNextDays["date"]; ok {
-			now := time.Now()
-		daysLoop:
-			for i, dateStr := range dateColumn {
-				if len(forecastNext) >= cfg.daysLimit {
-					break daysLoop
-				}
-
-				if dateStr == "" {
-					continue
-				}
-
-				currentDay := dayForecast{}
-				for name := range selectorsNextDays {
-					text := ""
-					if _, ok := dataNextDays[name]; ok && len(dataNextDays[name]) >= i+1 {
-						text = dataNextDays[name][i]
-					} else {
-						continue
-					}
-					text = clearNonprintInString(text)
-
-					switch name {
-					case "date":
-						datesRaw := reDate.FindAllString(text, 1)
-						if len(datesRaw) == 1 {
-							curDate, err := time.Parse("2006-01-02", datesRaw[0])
-							if err != nil || !curDate.Truncate(time.Hour*24).After(now.Truncate(time.Hour*24)) {
-								continue daysLoop
-							}
-							currentDay.DateHuman, currentDay.Date = formatDates(curDate)
-						}
-					case "desc":
-						currentDay.Desc = strings.ToLower(text)
-					case "temp":
-						currentDay.Temp = convertStrToInt(text)
-					case "temp_night":
-						currentDay.TempNight = convertStrToInt(text)
-					}
-				}
-
-				if currentDay.Date != "" {
-					forecastNext = append(forecastNext, currentDay)
-				}
-			}
-		}
+		forecastNext = parseNextDays(dataNextDays, cfg)
+		return nil
 	}
 
+	var details map[string]dayDetails
+	var errMain error
 	var wg sync.WaitGroup
-	wg.Add(2)
+	wg.Add(3)
 
 	go func() {
-		doc := html2data.FromURL(cfg.baseURL+cfg.city, html2data.URLCfg{UA: userAgent})
-		extractNowForecast(doc)
-		extractNextForecast(doc)
+		if mainDoc == nil {
+			doc := getPageDoc(ctx, cfg.page, cfg.pageURL(cfg.baseURL), cfg)
+			mainDoc = &doc
+		}
+		if errMain = extractNowForecast(*mainDoc); errMain == nil {
+			errMain = extractNextForecast(*mainDoc)
+		}
 		wg.Done()
 	}()
 
 	go func() {
 		// forecast by hours block
-		if !cfg.noToday {
-			docMini := html2data.FromURL(cfg.baseURLMini+cfg.city, html2data.URLCfg{UA: userAgent})
+		if !cfg.noToday && cfg.hoursDays == 0 {
+			docMini := getPageDoc(ctx, cfg.pageMini, cfg.pageURL(cfg.baseURLMini), cfg)
 			dataHours, err := docMini.GetDataNestedFirst(selectorByHoursRoot, selectorByHours)
 			if err == nil {
 				for _, row := range dataHours {
 					hour := convertStrToInt(row["hour"])
 					temp := convertStrToInt(row["temp"])
-					forecastByHours = append(forecastByHours, hourTemp{Hour: hour, Temp: temp, Icon: parseIcon(row["icon"])})
+					item := hourTemp{Hour: hour, Temp: temp, Icon: parseIcon(row["icon"])}
+					parseHourExtras(&item, row)
+					forecastByHours = append(forecastByHours, item)
 				}
 			}
 		}
@@ -281,8 +502,90 @@ func getWeather(cfg config) (map[string]interface{}, []hourTemp, []dayForecast)
 		wg.Done()
 	}()
 
+	go func() {
+		// morning, day, evening and night for days, forecast by hours for several days
+		if cfg.detailed && cfg.daysLimit > 0 || !cfg.noToday && cfg.hoursDays > 0 {
+			var err error
+			details, err = parseDetails(getPageDoc(ctx, cfg.pageDetails, cfg.detailsURL(), cfg), cfg)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			}
+		}
+
+		wg.Done()
+	}()
+
 	wg.Wait()
-	return forecastNow, forecastByHours, forecastNext
+	if errMain != nil {
+		return nil, nil, nil, errMain
+	}
+	if comfort, ok := computeComfort(forecastNow); ok {
+		forecastNow["comfort"] = comfort
+	}
+	if cfg.detailed {
+		addDayParts(forecastNext, details)
+	}
+	addAstro(forecastNext, cfg)
+	if !cfg.noToday && cfg.hoursDays > 0 {
+		forecastByHours = hoursFromDetails(details, cfg.hoursDays, cfg)
+	}
+	return forecastNow, forecastByHours, forecastNext, nil
+}
+
+//-----------------------------------------------------------------------------
+// forecast for days in window in time zone of city from columns of page data
+func parseNextDays(dataNextDays map[string][]string, cfg config) []dayForecast {
+	forecastNext := []dayForecast{}
+	reDate := regexp.MustCompile(`^\d{4}-\d{2}-\d{2}`)
+
+	if dateColumn, ok := dataNextDays["date"]; ok {
+	daysLoop:
+		for i, dateStr := range dateColumn {
+			if len(forecastNext) >= cfg.daysLimit {
+				break daysLoop
+			}
+
+			if dateStr == "" {
+				continue
+			}
+
+			currentDay := dayForecast{noTempNight: true}
+			for name := range selectorsNextDays {
+				text := ""
+				if _, ok := dataNextDays[name]; ok && len(dataNextDays[name]) >= i+1 {
+					text = dataNextDays[name][i]
+				} else {
+					continue
+				}
+				text = clearNonprintInString(text)
+
+				switch name {
+				case "date":
+					datesRaw := reDate.FindAllString(text, 1)
+					if len(datesRaw) == 1 {
+						curDate, err := cfg.parseDate(datesRaw[0])
+						if err != nil || !cfg.inDaysWindow(curDate) {
+							continue daysLoop
+						}
+						currentDay.DateHuman, currentDay.Date = formatDates(curDate, cfg.lang)
+					}
+				case "desc":
+					currentDay.Desc = strings.ToLower(text)
+				case "temp":
+					currentDay.Temp = convertStrToInt(text)
+				case "temp_night":
+					currentDay.TempNight = convertStrToInt(text)
+					currentDay.noTempNight = !strings.ContainsAny(text, "0123456789")
+				}
+			}
+
+			if currentDay.Date != "" {
+				forecastNext = append(forecastNext, currentDay)
+			}
+		}
+	}
+
+	return forecastNext
 }
 
 //-----------------------------------------------------------------------------
@@ -300,14 +603,15 @@ func parseIcon(cssClass string) string {
 //-----------------------------------------------------------------------------
 // render data as text or JSON
 func render(forecastNow map[string]interface{}, forecastByHours []hourTemp, forecastNext []dayForecast, cfg config) {
-	cityFromPage, ok := forecastNow["city"]
-	if !ok || cityFromPage == "" {
-		fmt.Fprintf(os.Stderr, "City %q not found\n", cfg.city)
-		os.Exit(1)
-	}
-	outWriter := getColorWriter(cfg.noColor)
+	exitIfCityNotFound(forecastNow, cfg)
+	cityFromPage := forecastNow["city"]
+	outWriter := getColorWriter(cfg.terminal)
 
 	if cfg.getJSON {
+		if cfg.location != nil {
+			forecastNow["place"] = placeFromTitle(cityFromPage.(string))
+			forecastNow["location"] = cfg.location
+		}
 		if !cfg.noToday && len(forecastByHours) > 0 {
 			forecastNow["by_hours"] = forecastByHours
 		}
@@ -321,37 +625,73 @@ func render(forecastNow map[string]interface{}, forecastByHours []hourTemp, fore
 		return
 	}
 
-	outWriter.Printf(cfg.ansiColourString("%s (<yellow>%s</>)\n"), cityFromPage, cfg.baseURL+cfg.city)
-	outWriter.Printf(
-		cfg.ansiColourString("Сейчас: <green>%d °C</> - <green>%s</>\n"),
-		forecastNow["term_now"],
-		forecastNow["desc_now"],
-	)
+	if cfg.summary {
+		fmt.Println(summarize(forecastNow, forecastByHours, forecastNext, cfg))
+		return
+	}
 
-	outWriter.Printf(cfg.ansiColourString("Давление: <green>%s</>\n"), forecastNow["pressure"])
-	outWriter.Printf(cfg.ansiColourString("Влажность: <green>%s</>\n"), forecastNow["humidity"])
-	outWriter.Printf(cfg.ansiColourString("Ветер: <green>%s</>\n"), forecastNow["wind"])
+	if cfg.oneline {
+		fmt.Println(renderOneline(forecastNow, cfg))
+		return
+	}
 
-	if !cfg.noToday && len(forecastByHours) > 0 {
-		textByHour := [4]string{}
-		for _, item := range forecastByHours {
-			textByHour[0] += fmt.Sprintf("%3d ", item.Hour)
-			textByHour[2] += fmt.Sprintf("%3d°", item.Temp)
-			icon, exists := icons[item.Icon]
-			if !exists {
-				icon = " "
-			}
-			textByHour[3] += fmt.Sprintf(cfg.ansiColourString("<blue>%3s</blue> "), icon)
+	if cfg.accessible {
+		for _, line := range renderAccessible(forecastNow, forecastByHours, forecastNext, cfg) {
+			outWriter.Println(cfg.ansiColourString(line))
 		}
-		textByHour[1] = cfg.ansiColourString("<grey+h>" + renderHisto(forecastByHours) + "</>")
+		return
+	}
 
-		outWriter.Println(strings.Repeat("─", len(forecastByHours)*4))
-		outWriter.Printf("%s\n%s\n%s\n%s\n",
-			cfg.ansiColourString("<grey+h>"+textByHour[0]+"</>"),
-			textByHour[1],
-			textByHour[2],
-			textByHour[3],
+	renderText(outWriter, forecastNow, forecastByHours, forecastNext, cfg)
+}
+
+//-----------------------------------------------------------------------------
+// forecast as text tables
+func renderText(outWriter terminalWriter, forecastNow map[string]interface{}, forecastByHours []hourTemp, forecastNext []dayForecast, cfg config) {
+	cityFromPage := forecastNow["city"]
+	if cfg.location != nil {
+		outWriter.Printf(cfg.ansiColourString("%s [%s] (<url>%s</>)\n"), placeFromTitle(cityFromPage.(string)), cfg.location, cfg.pageURL(cfg.baseURL))
+	} else {
+		outWriter.Printf(cfg.ansiColourString("%s (<url>%s</>)\n"), cityFromPage, cfg.pageURL(cfg.baseURL))
+	}
+	warnings, _ := forecastNow["warnings"].([]weatherWarning)
+	nowcast, _ := forecastNow["nowcast"].(string)
+	for _, line := range renderWarnings(warnings, nowcast, cfg) {
+		outWriter.Println(cfg.ansiColourString(line))
+	}
+	if cfg.view == "" || cfg.view == viewNow {
+		outWriter.Printf(
+			cfg.ansiColourString(cfg.tr("now")+": <"+cfg.tempColor(toFloat(forecastNow["term_now"]))+">%d "+cfg.tempUnit()+"</> - <value>%s</>\n"),
+			forecastNow["term_now"],
+			forecastNow["desc_now"],
 		)
+
+		outWriter.Printf(cfg.ansiColourString(cfg.tr("pressure")+": <value>%s</>\n"), forecastNow["pressure"])
+		outWriter.Printf(cfg.ansiColourString(cfg.tr("humidity")+": <value>%s</>\n"), forecastNow["humidity"])
+		outWriter.Printf(cfg.ansiColourString(cfg.tr("wind")+": <value>%s</>\n"), forecastNow["wind"])
+
+		if comfort, ok := forecastNow["comfort"].(comfortMetrics); ok && cfg.comfort {
+			for _, line := range renderComfort(comfort, cfg) {
+				outWriter.Println(cfg.ansiColourString(line))
+			}
+		}
+		if extra, ok := forecastNow["extra"].(extraPanels); ok {
+			for _, line := range renderExtraPanels(extra, cfg) {
+				outWriter.Println(cfg.ansiColourString(line))
+			}
+		}
+	}
+
+	if !cfg.noToday && len(forecastByHours) > 0 {
+		for _, hours := range splitHoursByDay(forecastByHours) {
+			header := ""
+			if date, err := cfg.parseDate(hours[0].Date); err == nil {
+				header, _ = formatDates(date, cfg.lang)
+			}
+			for _, line := range renderHours(hours, header, cfg) {
+				outWriter.Println(line)
+			}
+		}
 	}
 
 	if len(forecastNext) > 0 {
@@ -361,34 +701,134 @@ func render(forecastNow map[string]interface{}, forecastByHours []hourTemp, fore
 			descLength = todayForecastTableWidth
 		}
 
-		outWriter.Println(strings.Repeat("─", 27+descLength))
-		outWriter.Printf(
-			cfg.ansiColourString("<blue+h> %-10s %4s %-*s %8s</>\n"),
-			"дата",
-			"°C",
-			descLength, "погода",
-			"°C ночью",
+		astroWidth := 0
+		if forecastNext[0].Astro != nil {
+			astroWidth = cfg.astroWidth(forecastNext)
+		}
+
+		outWriter.Println(strings.Repeat("─", 27+descLength+astroWidth))
+		header := fmt.Sprintf(" %-10s %4s %-*s %8s",
+			cfg.tr("date"),
+			cfg.tempUnit(),
+			descLength, cfg.tr("weather"),
+			cfg.tempUnit()+" "+cfg.tr("night"),
 		)
-		outWriter.Println(strings.Repeat("─", 27+descLength))
+		if astroWidth > 0 {
+			header += fmt.Sprintf(" %-11s %-11s %5s %s", cfg.tr("twilight"), cfg.tr("sun"), cfg.tr("day_length"), cfg.tr("moon"))
+		}
+		outWriter.Println(cfg.ansiColourString("<header>" + header + "</>"))
+		outWriter.Println(strings.Repeat("─", 27+descLength+astroWidth))
 
-		weekendRe := regexp.MustCompile(`(сб|вс)`)
+		today := cfg.today()
 		for _, row := range forecastNext {
-			date := weekendRe.ReplaceAllString(row.DateHuman, cfg.ansiColourString("<red+h>$1</>"))
-			outWriter.Printf(
-				" %10s %3d° %-*s %7d°\n",
+			date := cfg.weekendRe().ReplaceAllString(row.DateHuman, cfg.ansiColourString("<weekend>$1</>"))
+			if row.Date == today {
+				date = cfg.ansiColourString("<today>" + row.DateHuman + "</>")
+			}
+			outWriter.Println(cfg.ansiColourString(fmt.Sprintf(
+				" %10s <%s>%3d°</> %-*s <%s>%7d°</>%s",
 				date,
-				row.Temp,
+				cfg.tempColor(float64(row.Temp)), row.Temp,
 				descLength,
 				row.Desc,
-				row.TempNight,
-			)
+				cfg.tempColor(float64(row.TempNight)), row.TempNight,
+				cfg.renderAstro(row.Astro),
+			)))
+			for _, line := range renderDayParts(row.Parts, descLength, cfg) {
+				outWriter.Println(cfg.ansiColourString(line))
+			}
 		}
+
+		if cfg.daysChart {
+			outWriter.Println(strings.Repeat("─", daysChartLineWidth))
+			for _, line := range renderDaysChart(forecastNext, cfg.today(), cfg) {
+				outWriter.Println(cfg.ansiColourString(line))
+			}
+		}
+	}
+}
+
+//-----------------------------------------------------------------------------
+// print error and exit if the page has no forecast for city or location
+func exitIfCityNotFound(forecastNow map[string]interface{}, cfg config) {
+	if err := cityNotFoundError(forecastNow, cfg); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+//-----------------------------------------------------------------------------
+// error if the page has no forecast for city or location, with suggestions of cities
+func cityNotFoundError(forecastNow map[string]interface{}, cfg config) error {
+	if cityFromPage, ok := forecastNow["city"]; ok && cityFromPage != "" {
+		return nil
 	}
+
+	if cfg.location != nil {
+		return fmt.Errorf("Location %s not found", cfg.location)
+	}
+	if suggestions := suggestCities(cfg.city, maxCitySuggestions); len(suggestions) > 0 {
+		return fmt.Errorf("City %q not found\nDid you mean: %s?", cfg.city, strings.Join(citySlugs(suggestions), ", "))
+	}
+	return fmt.Errorf("City %q not found", cfg.city)
+}
+
+//-----------------------------------------------------------------------------
+// render forecast by hours as chart or histogram with header, returns colored lines
+func renderHours(forecastByHours []hourTemp, header string, cfg config) []string {
+	result := []string{}
+	if cfg.chartHeight > 0 {
+		textTemp := strings.Repeat(" ", chartAxisWidth)
+		for _, item := range forecastByHours {
+			textTemp += fmt.Sprintf("<%s>%3d°</>", cfg.tempColor(float64(item.Temp)), item.Temp)
+		}
+
+		result = append(result, strings.Repeat("─", chartAxisWidth+len(forecastByHours)*chartHourWidth))
+		if header != "" {
+			result = append(result, cfg.ansiColourString("<header>"+header+"</>"))
+		}
+		for _, line := range renderChart(forecastByHours, cfg.nightHours(forecastByHours), cfg.chartHeight, cfg.braille) {
+			result = append(result, cfg.ansiColourString(line))
+		}
+		result = append(result, cfg.ansiColourString(textTemp))
+		for _, line := range renderHourlyExtras(forecastByHours, cfg) {
+			result = append(result, cfg.ansiColourString(strings.Repeat(" ", chartAxisWidth)+line))
+		}
+
+		return result
+	}
+
+	night := cfg.nightHours(forecastByHours)
+	textByHour := [4]string{}
+	textByHour[0] = hoursRow(forecastByHours, night)
+	for _, item := range forecastByHours {
+		textByHour[2] += cfg.ansiColourString(fmt.Sprintf("<%s>%3d°</>", cfg.tempColor(float64(item.Temp)), item.Temp))
+		icon, exists := icons[item.Icon]
+		if !exists {
+			icon = " "
+		}
+		textByHour[3] += fmt.Sprintf(cfg.ansiColourString("<icon>%3s</icon> "), icon)
+	}
+	textByHour[1] = cfg.ansiColourString("<hours>" + renderHisto(forecastByHours, night) + "</>")
+
+	result = append(result, strings.Repeat("─", len(forecastByHours)*4))
+	if header != "" {
+		result = append(result, cfg.ansiColourString("<header>"+header+"</>"))
+	}
+	result = append(result,
+		cfg.ansiColourString("<hours>"+textByHour[0]+"</>"),
+		textByHour[1],
+		textByHour[2],
+		textByHour[3],
+	)
+	for _, line := range renderHourlyExtras(forecastByHours, cfg) {
+		result = append(result, cfg.ansiColourString(line))
+	}
+
+	return result
 }
 
 //-----------------------------------------------------------------------------
 func main() {
-	cfg := getParams()
-	forecastNow, forecastByHours, forecastNext := getWeather(cfg)
-	render(forecastNow, forecastByHours, forecastNext, cfg)
+	os.Exit(runCommand(os.Args[1:]))
 }
@@ -1,6 +1,9 @@
 package main
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 func Test_clearIntegerInString(t *testing.T) {
 	testData := []struct {
@@ -91,3 +94,77 @@ func Test_parseIcon(t *testing.T) {
 		}
 	}
 }
+
+func Test_getWeatherSavedPages(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.city, cfg.page, cfg.pageMini, cfg.asOf = "moscow", "testdata/moscow.html", "testdata/moscow-mini.html", "2021-10-23 23:30"
+	if err := validateConfig(&cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	forecastNow, forecastByHours, forecastNext := getWeather(&cfg)
+	if forecastNow["term_now"] != 12 || forecastNow["desc_now"] != "Облачно с прояснениями" || forecastNow["wind"] != "3 м/с, СЗ" {
+		t.Errorf("getWeather() forecast now = %v", forecastNow)
+	}
+	if extra, ok := forecastNow["extra"].(extraPanels); !ok || *extra.AirQuality != 35 || *extra.UVIndex != 1 || *extra.Geomagnetic != 2 || extra.WaterTemp != nil {
+		t.Errorf("getWeather() extra panels = %+v", forecastNow["extra"])
+	}
+	if len(forecastByHours) != 12 {
+		t.Fatalf("getWeather() forecast by hours = %v", forecastByHours)
+	}
+	if hour := forecastByHours[2]; hour.Hour != 17 || hour.Temp != 11 || hour.Icon != "icon_rain" ||
+		*hour.PrecipProb != 70 || *hour.Precip != 0.6 || *hour.Wind != 5 || *hour.FeelsLike != 8 {
+		t.Errorf("getWeather() forecast by hours = %+v", hour)
+	}
+	if hour := forecastByHours[11]; hour.PrecipProb != nil || hour.Precip != nil || hour.Wind != nil || hour.FeelsLike != nil {
+		t.Errorf("getWeather() hour without extra fields = %+v", hour)
+	}
+
+	wantDates := []string{"2021-10-24", "2021-10-25", "2021-10-26"}
+	if len(forecastNext) != len(wantDates) {
+		t.Fatalf("getWeather() forecast next days = %v", forecastNext)
+	}
+	for i, date := range wantDates {
+		if forecastNext[i].Date != date {
+			t.Errorf("getWeather() next day %d = %v, want %s", i, forecastNext[i], date)
+		}
+	}
+}
+
+func Test_getWeatherDayParts(t *testing.T) {
+	for _, detailed := range []bool{false, true} {
+		cfg := defaultConfig()
+		cfg.city, cfg.page, cfg.pageDetails, cfg.asOf = "moscow", "testdata/moscow.html", "testdata/moscow-details.html", "2021-10-22 15:00"
+		cfg.hoursDays, cfg.detailed = 2, detailed
+		if err := validateConfig(&cfg); err != nil {
+			t.Fatal(err)
+		}
+
+		_, forecastByHours, forecastNext := getWeather(&cfg)
+		if len(forecastByHours) == 0 || forecastByHours[0].Date != "2021-10-23" {
+			t.Errorf("getWeather() detailed=%v forecast by hours from details = %+v", detailed, forecastByHours)
+		}
+		if len(forecastNext) == 0 || (len(forecastNext[0].Parts) > 0) != detailed {
+			t.Errorf("getWeather() detailed=%v day parts = %+v", detailed, forecastNext)
+		}
+	}
+}
+
+func Test_renderHours(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.noColor = true
+	hours := []hourTemp{{Hour: 0, Temp: 1}, {Hour: 12, Temp: 5, Icon: "icon_rain"}}
+
+	got := renderHours(hours, "23.10 (сб)", cfg)
+	want := []string{
+		"────────",
+		"23.10 (сб)",
+		"  0  12 ",
+		"▁▂▄▆████",
+		"  1°  5°",
+		"      ☂ ",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("renderHours() =\n%q\nwant\n%q", got, want)
+	}
+}